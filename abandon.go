@@ -0,0 +1,50 @@
+package service
+
+// Abandon gives up on all services that did not return from Run although the container is shutting down,
+// e.g. after WaitAllStopped returned because its ctx is done, see StuckServices.
+// Abandoned services are set to StateAbandoned and detached from the container, so later calls to
+// WaitAllStopped do not wait for them again and the container can be started again.
+// The goroutines of abandoned services keep running until Run returns, their result is only logged.
+// Returns the names of the abandoned services and calls the OnAbandon callbacks if any service was abandoned.
+func (c *Container) Abandon() []string {
+	c.mu.Lock()
+	var abandoned []*runContext
+	if c.shuttingDown {
+		for _, rc := range c.orderedRunContexts() {
+			if rc.running && !rc.abandoned {
+				rc.abandoned = true
+				rc.running = false
+				rc.state = StateAbandoned
+				close(rc.done)
+				abandoned = append(abandoned, rc)
+			}
+		}
+	}
+	callbacks := append([]func(names []string){}, c.abandonCallbacks...)
+	c.mu.Unlock()
+	if len(abandoned) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(abandoned))
+	for _, rc := range abandoned {
+		names = append(names, rc.service.name)
+		c.serviceLogger(rc.service).Error("Abandoned service that did not stop")
+		c.emitEvent(EventAbandoned, rc.service.name, nil)
+	}
+	c.notifyStatusChange()
+	c.updateState()
+	for _, f := range callbacks {
+		c.callSafe("abandon callback", func() {
+			f(names)
+		})
+	}
+	return names
+}
+
+// OnAbandon registers a callback that is called with the names of the services abandoned by Abandon
+func (c *Container) OnAbandon(f func(names []string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.abandonCallbacks = append(c.abandonCallbacks, f)
+}
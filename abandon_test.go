@@ -0,0 +1,57 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbandon(t *testing.T) {
+	c := service.NewContainer()
+	release := make(chan struct{})
+	defer close(release)
+	service.New("stuck").Run(func(ctx context.Context) error {
+		<-release
+		return nil
+	}).Register(c)
+	service.New("ok").Run(blockUntilDone).Register(c)
+	var abandoned []string
+	c.OnAbandon(func(names []string) {
+		abandoned = names
+	})
+	abandonedEvent := make(chan struct{})
+	c.Subscribe(func(e service.Event) {
+		if e.Type == service.EventAbandoned {
+			close(abandonedEvent)
+		}
+	})
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Empty(t, c.Abandon(), "running services are not abandoned")
+
+	c.StopAll()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	c.WaitAllStopped(ctx)
+
+	assert.Equal(t, []string{"stuck"}, c.Abandon())
+	assert.Equal(t, []string{"stuck"}, abandoned)
+	state, err := c.ServiceState("stuck")
+	require.NoError(t, err)
+	assert.Equal(t, service.StateAbandoned, state)
+	assert.Empty(t, c.StuckServices())
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	c.WaitAllStopped(ctx)
+	assert.NoError(t, ctx.Err(), "abandoned services must not be awaited")
+	assert.Equal(t, service.ContainerStopped, c.State())
+	select {
+	case <-abandonedEvent:
+	case <-time.After(time.Second):
+		t.Fatal("expected abandoned event")
+	}
+}
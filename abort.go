@@ -0,0 +1,35 @@
+package service
+
+import (
+	"fmt"
+)
+
+// AbortStart aborts a StartAll call that is in progress, e.g. from a signal handler while services are still initializing.
+// The context of the running Init is canceled, remaining services are not initialized, all already started
+// services are stopped and StartAll returns an error wrapping reason.
+// Returns false if the container is not starting.
+func (c *Container) AbortStart(reason error) bool {
+	c.mu.Lock()
+	if !c.starting {
+		c.mu.Unlock()
+		return false
+	}
+	if reason == nil {
+		reason = fmt.Errorf("start aborted")
+	}
+	c.abortErr = fmt.Errorf("start of container '%s' aborted: %w", c.name, reason)
+	abortErr := c.abortErr
+	cancel := c.runCtxCancel
+	c.mu.Unlock()
+
+	c.log.Warn("Aborting start", "reason", reason, "container", c.name, "run", c.RunID())
+	cancel(abortErr)
+	return true
+}
+
+// startAborted returns the error set by AbortStart
+func (c *Container) startAborted() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.abortErr
+}
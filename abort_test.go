@@ -0,0 +1,45 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbortStart(t *testing.T) {
+	c := service.NewContainer()
+	assert.False(t, c.AbortStart(errors.New("not starting")))
+
+	s1 := &testService{Name: "s1"}
+	c.Register(s1)
+	initStarted := make(chan struct{})
+	service.New("slow").Init(func(ctx context.Context) error {
+		close(initStarted)
+		<-ctx.Done()
+		return ctx.Err()
+	}).Register(c)
+	s3 := &testService{Name: "s3"}
+	c.Register(s3)
+
+	reason := errors.New("SIGTERM received")
+	go func() {
+		<-initStarted
+		assert.True(t, c.AbortStart(reason))
+	}()
+
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, reason)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	c.WaitAllStopped(shutdownCtx)
+	assertServiceOnlyInitialized(t, s1)
+	assertServiceNeverStarted(t, s3)
+	assert.Equal(t, []string{s3.String()}, c.StartReport().Skipped)
+}
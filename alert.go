@@ -0,0 +1,80 @@
+package service
+
+import (
+	"time"
+)
+
+// Severity of an Alert
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "Info"
+	case SeverityWarning:
+		return "Warning"
+	case SeverityCritical:
+		return "Critical"
+	default:
+		return "Unknown"
+	}
+}
+
+// Alert describes a significant condition of a service that might require human attention, see WithAlerter
+type Alert struct {
+	Container string
+	Service   string
+	Code      ErrorCode
+	Severity  Severity
+	Message   string
+	// Err that caused the alert, if any
+	Err  error
+	Time time.Time
+}
+
+// WithAlerter calls f for significant conditions of services, independent of the logger, e.g. to page on call:
+// crash loops and panics are critical, exceeded stop timeouts and failed health checks are warnings,
+// see ErrorCode and WithRestartOnUnhealthy. f is called in a separate goroutine, alerts might be delivered
+// out of order.
+func WithAlerter(f func(a Alert)) Option {
+	return func(c *Container) {
+		c.alerter = f
+	}
+}
+
+// alertError raises an alert for significant service errors
+func (c *Container) alertError(e ServiceError) {
+	switch e.Code {
+	case CodeCrashLoop:
+		c.alert(e.Service, e.Code, SeverityCritical, "service is crash looping", e.Err)
+	case CodePanic:
+		c.alert(e.Service, e.Code, SeverityCritical, "service panicked", e.Err)
+	case CodeStopTimeout:
+		c.alert(e.Service, e.Code, SeverityWarning, "service did not stop within its stop timeout", e.Err)
+	}
+}
+
+// alert calls the alerter if any, see WithAlerter
+func (c *Container) alert(service string, code ErrorCode, severity Severity, msg string, err error) {
+	if c.alerter == nil {
+		return
+	}
+	a := Alert{
+		Container: c.name,
+		Service:   service,
+		Code:      code,
+		Severity:  severity,
+		Message:   msg,
+		Err:       err,
+		Time:      time.Now(),
+	}
+	go c.callSafe("alerter", func() {
+		c.alerter(a)
+	}, "name", service, "code", code)
+}
@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type alertRecorder struct {
+	mu     sync.Mutex
+	alerts []service.Alert
+}
+
+func (r *alertRecorder) alert(a service.Alert) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, a)
+}
+
+func (r *alertRecorder) codes() map[service.ErrorCode]service.Severity {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	codes := map[service.ErrorCode]service.Severity{}
+	for _, a := range r.alerts {
+		codes[a.Code] = a.Severity
+	}
+	return codes
+}
+
+func TestWithAlerter(t *testing.T) {
+	r := &alertRecorder{}
+	c := service.NewContainer(service.WithName("app"), service.WithAlerter(r.alert))
+	service.New("flaky").Run(func(ctx context.Context) error {
+		return errors.New("crashed")
+	}).Restart(service.RestartOnFailure, service.Backoff{Initial: time.Millisecond, MaxRestarts: 1}).Register(c)
+	service.New("slow").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}).StopTimeout(5 * time.Millisecond).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	require.Error(t, c.WaitAllStopped(context.Background()))
+
+	require.Eventually(t, func() bool {
+		return len(r.codes()) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, map[service.ErrorCode]service.Severity{
+		service.CodeCrashLoop:   service.SeverityCritical,
+		service.CodeStopTimeout: service.SeverityWarning,
+	}, r.codes())
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.alerts {
+		assert.Equal(t, "app", a.Container)
+		assert.NotEmpty(t, a.Message)
+		assert.Error(t, a.Err)
+		if a.Code == service.CodeCrashLoop {
+			assert.Equal(t, "flaky", a.Service)
+		}
+	}
+}
+
+func TestWithAlerter_unhealthy(t *testing.T) {
+	r := &alertRecorder{}
+	c := service.NewContainer(service.WithAlerter(r.alert), service.WithHealthCheckInterval(5*time.Millisecond))
+	service.New("worker").Run(blockUntilDone).Health(func(ctx context.Context) error {
+		return errors.New("degraded")
+	}).RestartOnUnhealthy(100, service.Backoff{}).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	require.Eventually(t, func() bool {
+		return r.codes()[service.CodeUnhealthy] == service.SeverityWarning
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
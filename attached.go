@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// attachedTask is a goroutine started with GoAttached
+type attachedTask struct {
+	name      string
+	state     ServiceState
+	err       error
+	startedAt time.Time
+	stoppedAt time.Time
+	done      chan struct{}
+}
+
+// GoAttached runs f in a goroutine that is attached to the container, for lightweight background work
+// that does not warrant a full service. The ctx of f is canceled when the container stops and
+// WaitAllStopped waits until f returned. Attached tasks are listed after the services in Status,
+// see ServiceStatus.Attached. Errors are logged and reported in Status, they do not stop the container.
+// Returns an error when the container is not running or shutting down.
+func (c *Container) GoAttached(name string, f func(ctx context.Context) error) error {
+	c.mu.Lock()
+	if c.runCtx == nil || c.runCtx.Err() != nil || c.shuttingDown {
+		c.mu.Unlock()
+		return fmt.Errorf("container '%s' is not running, can not attach task '%s'", c.name, name)
+	}
+	task := &attachedTask{
+		name:      name,
+		state:     StateRunning,
+		startedAt: time.Now(),
+		done:      make(chan struct{}),
+	}
+	c.attached = append(c.attached, task)
+	ctx := c.runCtx
+	c.mu.Unlock()
+	c.notifyStatusChange()
+
+	logger := c.log.With("task", name, "container", c.name, "run", c.RunID())
+	go func() {
+		defer close(task.done)
+		logger.Debug("Starting attached task")
+		err := f(withLogger(ctx, logger))
+		c.mu.Lock()
+		task.err = err
+		task.stoppedAt = time.Now()
+		task.state = StateStopped
+		if err != nil {
+			task.state = StateFailed
+		}
+		c.mu.Unlock()
+		if err != nil {
+			logger.Error("Attached task failed", "error", err)
+		} else {
+			logger.Debug("Attached task stopped")
+		}
+		c.notifyStatusChange()
+	}()
+	return nil
+}
+
+// attachedStatus returns the status of all attached tasks, c.mu must be held by the caller
+func (c *Container) attachedStatus() []ServiceStatus {
+	status := make([]ServiceStatus, 0, len(c.attached))
+	for _, t := range c.attached {
+		status = append(status, ServiceStatus{
+			Name:      t.name,
+			State:     t.state,
+			Err:       t.err,
+			StartedAt: t.startedAt,
+			StoppedAt: t.stoppedAt,
+			Attached:  true,
+		})
+	}
+	return status
+}
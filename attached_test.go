@@ -0,0 +1,52 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoAttached(t *testing.T) {
+	c := service.NewContainer()
+	assert.Error(t, c.GoAttached("early", blockUntilDone))
+	service.New("worker").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	taskErr := errors.New("task failed")
+	require.NoError(t, c.GoAttached("failing", func(ctx context.Context) error {
+		return taskErr
+	}))
+	stopped := make(chan struct{})
+	require.NoError(t, c.GoAttached("watcher", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		close(stopped)
+		return nil
+	}))
+
+	assert.Eventually(t, func() bool {
+		status := c.Status()
+		return len(status) == 3 && status[1].State == service.StateFailed
+	}, time.Second, 5*time.Millisecond)
+	status := c.Status()
+	assert.False(t, status[0].Attached)
+	assert.Equal(t, "failing", status[1].Name)
+	assert.True(t, status[1].Attached)
+	assert.ErrorIs(t, status[1].Err, taskErr)
+	assert.Equal(t, service.StateRunning, status[2].State)
+	assert.Equal(t, 1, c.RunningCount(), "attached tasks are no services")
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("WaitAllStopped must wait for attached tasks")
+	}
+	assert.Error(t, c.GoAttached("late", blockUntilDone))
+}
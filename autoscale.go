@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// AutoscaleConfig configures an autoscaler for a service family, see NewAutoscaler
+type AutoscaleConfig struct {
+	// Min and Max number of replicas
+	Min int
+	Max int
+	// Load returns the current load, e.g. the depth of a queue
+	Load func(ctx context.Context) (float64, error)
+	// TargetPerReplica is the load a single replica is expected to handle, default is 1
+	TargetPerReplica float64
+	// Interval in which the load is checked, default is 10 seconds
+	Interval time.Duration
+	// ScaleUpCooldown is the minimum time between the last scaling and scaling up
+	ScaleUpCooldown time.Duration
+	// ScaleDownCooldown is the minimum time between the last scaling and scaling down
+	ScaleDownCooldown time.Duration
+	// OnScale is called after the number of replicas changed
+	OnScale func(e ScaleEvent)
+}
+
+// ScaleEvent describes a change of the number of replicas of a service family
+type ScaleEvent struct {
+	Prefix string
+	From   int
+	To     int
+	Load   float64
+	Time   time.Time
+}
+
+type autoscaler struct {
+	c        *Container
+	prefix   string
+	cfg      AutoscaleConfig
+	replicas int
+	// lastScale is the time of the last change of replicas
+	lastScale time.Time
+}
+
+// NewAutoscaler returns a service that scales the number of instances of the service family registered with prefix
+// between cfg.Min and cfg.Max replicas based on the load reported by cfg.Load.
+// Replicas are started with EnsureInstance using the keys "0" to "<replicas-1>", replicas that are still
+// running when the autoscaler is restarted are adopted.
+// The autoscaler must be registered in the same container as the factory.
+func NewAutoscaler(c *Container, prefix string, cfg AutoscaleConfig) Runner {
+	if cfg.TargetPerReplica <= 0 {
+		cfg.TargetPerReplica = 1
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	if cfg.Max < cfg.Min {
+		cfg.Max = cfg.Min
+	}
+	return &autoscaler{c: c, prefix: prefix, cfg: cfg}
+}
+
+func (a *autoscaler) String() string {
+	return "autoscaler:" + a.prefix
+}
+
+func (a *autoscaler) Run(ctx context.Context) error {
+	// Adopt the replicas still running after a restart, so replicas above Min are scaled down later
+	replicas := a.runningReplicas()
+	for i := range replicas {
+		// Replaces replicas that returned in the meantime
+		err := a.c.EnsureInstance(ctx, a.prefix, strconv.Itoa(i))
+		if err != nil {
+			return fmt.Errorf("failed to adopt replicas of %s: %w", a.prefix, err)
+		}
+	}
+	a.replicas = replicas
+	a.lastScale = time.Time{}
+	err := a.scaleTo(ctx, max(a.cfg.Min, min(a.cfg.Max, replicas)), 0)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		load, err := a.cfg.Load(ctx)
+		if err != nil {
+			a.c.log.Warn("Failed to get load for autoscaling", "family", a.prefix, "error", err, "container", a.c.name)
+			continue
+		}
+		desired := a.desiredReplicas(load)
+		if desired == a.replicas {
+			continue
+		}
+		cooldown := a.cfg.ScaleDownCooldown
+		if desired > a.replicas {
+			cooldown = a.cfg.ScaleUpCooldown
+		}
+		if time.Since(a.lastScale) < cooldown {
+			continue
+		}
+		err = a.scaleTo(ctx, desired, load)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+	}
+}
+
+// runningReplicas returns the number of replicas up to the highest running replica key
+func (a *autoscaler) runningReplicas() int {
+	replicas := 0
+	for _, key := range a.c.Instances(a.prefix) {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < replicas {
+			continue
+		}
+		if a.c.IsServiceRunning(InstanceName(a.prefix, key)) {
+			replicas = i + 1
+		}
+	}
+	return replicas
+}
+
+func (a *autoscaler) desiredReplicas(load float64) int {
+	desired := int(math.Ceil(load / a.cfg.TargetPerReplica))
+	return max(a.cfg.Min, min(a.cfg.Max, desired))
+}
+
+func (a *autoscaler) scaleTo(ctx context.Context, replicas int, load float64) error {
+	from := a.replicas
+	for a.replicas < replicas {
+		err := a.c.EnsureInstance(ctx, a.prefix, strconv.Itoa(a.replicas))
+		if err != nil {
+			return fmt.Errorf("failed to scale up %s: %w", a.prefix, err)
+		}
+		a.replicas++
+	}
+	for a.replicas > replicas {
+		err := a.c.StopInstance(a.prefix, strconv.Itoa(a.replicas-1))
+		if err != nil {
+			return fmt.Errorf("failed to scale down %s: %w", a.prefix, err)
+		}
+		a.replicas--
+	}
+	if from == a.replicas {
+		return nil
+	}
+
+	a.lastScale = time.Now()
+	a.c.log.Info("Scaled service family", "family", a.prefix, "from", from, "to", a.replicas, "load", load, "container", a.c.name)
+	if a.cfg.OnScale != nil {
+		e := ScaleEvent{Prefix: a.prefix, From: from, To: a.replicas, Load: load, Time: a.lastScale}
+		a.c.callSafe("scale callback", func() {
+			a.cfg.OnScale(e)
+		})
+	}
+	return nil
+}
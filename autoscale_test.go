@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoscaler(t *testing.T) {
+	c := service.NewContainer()
+	c.RegisterFactory("worker", newBlockingRunner)
+
+	queueDepth := atomic.Int64{}
+	queueDepth.Store(25)
+	mu := sync.Mutex{}
+	var events []service.ScaleEvent
+	c.Register(service.NewAutoscaler(c, "worker", service.AutoscaleConfig{
+		Min:              1,
+		Max:              4,
+		TargetPerReplica: 10,
+		Interval:         5 * time.Millisecond,
+		Load: func(ctx context.Context) (float64, error) {
+			return float64(queueDepth.Load()), nil
+		},
+		OnScale: func(e service.ScaleEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		},
+	}))
+
+	ctx := context.Background()
+	require.NoError(t, c.StartAll(ctx))
+
+	assert.Eventually(t, func() bool {
+		return len(c.Instances("worker")) == 3
+	}, time.Second, time.Millisecond)
+
+	queueDepth.Store(1000)
+	assert.Eventually(t, func() bool {
+		return len(c.Instances("worker")) == 4
+	}, time.Second, time.Millisecond)
+
+	queueDepth.Store(0)
+	assert.Eventually(t, func() bool {
+		return len(c.Instances("worker")) == 1
+	}, time.Second, time.Millisecond)
+
+	c.StopAll()
+	c.WaitAllStopped(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, events, 4)
+	assert.Equal(t, 0, events[0].From)
+	assert.Equal(t, 1, events[0].To)
+	assert.Equal(t, 4, events[2].To)
+	assert.Equal(t, 1, events[3].To)
+}
+
+func TestAutoscaler_restart(t *testing.T) {
+	c := service.NewContainer()
+	c.RegisterFactory("worker", newBlockingRunner)
+	c.Register(service.NewAutoscaler(c, "worker", service.AutoscaleConfig{
+		Min:      2,
+		Max:      4,
+		Interval: time.Hour,
+		Load: func(ctx context.Context) (float64, error) {
+			return 0, nil
+		},
+	}))
+
+	ctx := context.Background()
+	for range 2 {
+		require.NoError(t, c.StartAll(ctx))
+		assert.Eventually(t, func() bool {
+			return len(c.Instances("worker")) == 2
+		}, time.Second, time.Millisecond)
+		require.NoError(t, c.StopAllAndWait(ctx))
+	}
+}
+
+func TestAutoscaler_restartKeepsReplicas(t *testing.T) {
+	c := service.NewContainer()
+	c.RegisterFactory("worker", newBlockingRunner)
+	queueDepth := atomic.Int64{}
+	queueDepth.Store(4)
+	loadAvailable := atomic.Bool{}
+	loadAvailable.Store(true)
+	c.Register(service.NewAutoscaler(c, "worker", service.AutoscaleConfig{
+		Min:      1,
+		Max:      4,
+		Interval: 5 * time.Millisecond,
+		Load: func(ctx context.Context) (float64, error) {
+			if !loadAvailable.Load() {
+				return 0, errors.New("no load")
+			}
+			return float64(queueDepth.Load()), nil
+		},
+	}))
+
+	ctx := context.Background()
+	require.NoError(t, c.StartAll(ctx))
+	assert.Eventually(t, func() bool {
+		return len(c.Instances("worker")) == 4
+	}, time.Second, time.Millisecond)
+
+	// The load drops while the autoscaler restarts
+	loadAvailable.Store(false)
+	queueDepth.Store(1)
+	require.NoError(t, c.Restart(ctx, "autoscaler:worker"))
+	loadAvailable.Store(true)
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"0"}, c.Instances("worker"))
+	}, time.Second, time.Millisecond, "replicas of the previous run must be scaled down")
+
+	require.NoError(t, c.StopAllAndWait(ctx))
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// BackgroundInit sets a heavy initialization that runs in the background while Run is already called,
+// e.g. to meet startup probes without blocking the container. Until f returned, the service is reported
+// as StateWarming and is not ready, see Readier. When f returns an error, the context of Run is canceled
+// and the service fails with that error. f is called again when Run is restarted.
+func (b *Builder) BackgroundInit(f InitFunc) *Builder {
+	b.backgroundInit = f
+	return b
+}
+
+// backgroundInitService runs the background init of a Builder service next to Run, see Builder.BackgroundInit
+type backgroundInitService struct {
+	*genericService
+	backgroundInit InitFunc
+
+	mu sync.Mutex
+	// done is closed when the background init of the current Run returned
+	done    chan struct{}
+	err     error
+	started bool
+}
+
+func newBackgroundInitService(s *genericService, f InitFunc) *backgroundInitService {
+	return &backgroundInitService{
+		genericService: s,
+		backgroundInit: f,
+		done:           make(chan struct{}),
+	}
+}
+
+// primary returns the wrapped service to apply the options of the Builder, see newServiceInfo
+func (s *backgroundInitService) primary() Runner {
+	return s.genericService
+}
+
+func (s *backgroundInitService) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.started {
+		// Restarted, initialize again. The init of the previous Run might still be running and closes its own channel.
+		s.done = make(chan struct{})
+		s.err = nil
+	}
+	s.started = true
+	done := s.done
+	s.mu.Unlock()
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go func() {
+		err := s.backgroundInit(runCtx)
+		if err != nil {
+			err = fmt.Errorf("background init failed: %w", err)
+			cancel(err)
+		}
+		s.mu.Lock()
+		if s.done == done {
+			s.err = err
+		}
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	err := s.genericService.Run(runCtx)
+	if cause := context.Cause(runCtx); ctx.Err() == nil && cause != nil && !errors.Is(cause, context.Canceled) {
+		return errors.Join(cause, err)
+	}
+	return err
+}
+
+// Ready blocks until the background init is done, see Readier
+func (s *backgroundInitService) Ready(ctx context.Context) error {
+	s.mu.Lock()
+	done := s.done
+	s.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// warming returns true while the background init is running
+func (s *backgroundInitService) warming() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"log/slog"
+)
+
+// Blueprint is a snapshot of the wiring of a Container: the options it was created with,
+// the logger, all registered services, factories, middlewares, callbacks and flushers.
+// Use NewContainerFromBlueprint to create any number of fresh, identical containers from it.
+//
+// Resources managed with ManageCloser are not part of the blueprint, they are closed once by the container
+// they were added to.
+//
+// The services themselves are not copied. All containers created from the same blueprint
+// share the registered Runner instances, thus services must support being started again.
+type Blueprint struct {
+	opts              []Option
+	log               *slog.Logger
+	services          []serviceInfo
+	shutdownCallbacks []shutdownCallback
+	flushers          []flusher
+	parentCanceled    []func(cause error)
+	stateCallbacks    []func(from, to ContainerState)
+	abandonCallbacks  []func(names []string)
+	runMiddlewares    []RunMiddleware
+	initMiddlewares   []InitMiddleware
+	factories         map[string]*serviceFamily
+}
+
+// Blueprint captures the current wiring of the container.
+// Services registered after the call are not part of the blueprint.
+func (c *Container) Blueprint() Blueprint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bp := Blueprint{
+		opts:      append([]Option{}, c.opts...),
+		log:       c.log,
+		services:  make([]serviceInfo, 0, len(c.services)),
+		factories: map[string]*serviceFamily{},
+	}
+	bp.shutdownCallbacks = append([]shutdownCallback{}, c.shutdownCallbacks...)
+	bp.flushers = append([]flusher{}, c.flushers...)
+	bp.parentCanceled = append([]func(cause error){}, c.parentCanceledCallbacks...)
+	bp.stateCallbacks = append([]func(from, to ContainerState){}, c.stateCallbacks...)
+	bp.abandonCallbacks = append([]func(names []string){}, c.abandonCallbacks...)
+	bp.runMiddlewares = append([]RunMiddleware{}, c.runMiddlewares...)
+	bp.initMiddlewares = append([]InitMiddleware{}, c.initMiddlewares...)
+	for _, s := range c.services {
+		if s.family != "" {
+			// Instances are created on demand by the factory
+			continue
+		}
+		bp.services = append(bp.services, *s)
+	}
+	for prefix, f := range c.families {
+		bp.factories[prefix] = f
+	}
+	return bp
+}
+
+// ServiceNames returns the names of all services in the blueprint in order of registration
+func (bp Blueprint) ServiceNames() []string {
+	names := make([]string, 0, len(bp.services))
+	for _, s := range bp.services {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// NewContainerFromBlueprint creates a new container that is wired exactly like the container the blueprint was taken from.
+// The new container is not started yet.
+func NewContainerFromBlueprint(bp Blueprint) *Container {
+	c := NewContainer(bp.opts...)
+	if bp.log != nil {
+		c.log = bp.log
+	}
+	for i := range bp.services {
+		s := bp.services[i]
+		c.services = append(c.services, &s)
+	}
+	c.shutdownCallbacks = append(c.shutdownCallbacks, bp.shutdownCallbacks...)
+	c.flushers = append(c.flushers, bp.flushers...)
+	c.parentCanceledCallbacks = append(c.parentCanceledCallbacks, bp.parentCanceled...)
+	c.stateCallbacks = append(c.stateCallbacks, bp.stateCallbacks...)
+	c.abandonCallbacks = append(c.abandonCallbacks, bp.abandonCallbacks...)
+	c.runMiddlewares = append(c.runMiddlewares, bp.runMiddlewares...)
+	c.initMiddlewares = append(c.initMiddlewares, bp.initMiddlewares...)
+	for prefix, f := range bp.factories {
+		c.RegisterFactory(prefix, f.factory, f.opts...)
+	}
+	return c
+}
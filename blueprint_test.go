@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContainerFromBlueprint(t *testing.T) {
+	c := service.NewContainer(service.WithName("app"))
+	runs := 0
+	service.New("s1").Run(func(ctx context.Context) error {
+		runs++
+		<-ctx.Done()
+		return nil
+	}).Register(c)
+	service.New("s2").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}).Register(c)
+
+	bp := c.Blueprint()
+	assert.Equal(t, []string{"s1", "s2"}, bp.ServiceNames())
+
+	for i := 0; i < 3; i++ {
+		fresh := service.NewContainerFromBlueprint(bp)
+		assert.Equal(t, "app", fresh.Name())
+
+		err := fresh.StartAll(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, fresh.ServiceNames(), 2)
+
+		fresh.StopAll()
+		fresh.WaitAllStopped(context.Background())
+		assert.Len(t, fresh.ServiceErrors(), 0)
+	}
+	assert.Equal(t, 3, runs)
+	assert.False(t, c.IsRunning(), "original container must not be started")
+}
+
+func TestNewContainerFromBlueprint_middlewaresAndCallbacks(t *testing.T) {
+	c := service.NewContainer()
+	var calls []string
+	c.Use(func(next service.RunFunc) service.RunFunc {
+		return func(ctx context.Context) error {
+			calls = append(calls, "run")
+			return next(ctx)
+		}
+	})
+	c.UseInit(func(next service.InitFunc) service.InitFunc {
+		return func(ctx context.Context) error {
+			calls = append(calls, "init")
+			return next(ctx)
+		}
+	})
+	stopped := atomic.Bool{}
+	c.OnStateChange(func(from, to service.ContainerState) {
+		if to == service.ContainerStopped {
+			stopped.Store(true)
+		}
+	})
+	service.New("s1").Init(func(ctx context.Context) error {
+		return nil
+	}).Run(blockUntilDone).Register(c)
+
+	fresh := service.NewContainerFromBlueprint(c.Blueprint())
+	require.NoError(t, fresh.StartAll(context.Background()))
+	require.NoError(t, fresh.StopAllAndWait(context.Background()))
+
+	assert.Equal(t, []string{"init", "run"}, calls)
+	assert.Eventually(t, func() bool { return stopped.Load() }, time.Second, time.Millisecond)
+}
@@ -2,20 +2,22 @@ package service
 
 import (
 	"context"
+	"time"
 )
 
 type Builder struct {
-	name string
-	init InitFunc
-	run  RunFunc
+	name   string
+	init   InitFunc
+	run    RunFunc
+	health func(ctx context.Context) error
+	// backgroundInit runs next to Run, see BackgroundInit
+	backgroundInit InitFunc
+	opts           []RegisterOption
 }
 
 func New(name string) *Builder {
 	b := &Builder{
 		name: name,
-		init: func(ctx context.Context) error {
-			return nil
-		},
 		run: func(ctx context.Context) error {
 			return nil
 		},
@@ -33,10 +35,117 @@ func (b *Builder) Run(f RunFunc) *Builder {
 	return b
 }
 
+// Health sets the health check of the service, see HealthChecker
+func (b *Builder) Health(f func(ctx context.Context) error) *Builder {
+	b.health = f
+	return b
+}
+
+// ShutdownPriority sets the priority used to order the shutdown, see ShutdownPrioritizer
+func (b *Builder) ShutdownPriority(p int) *Builder {
+	return b.With(WithShutdownPriority(p))
+}
+
+// InitBefore initializes the service before the given init barriers, see WithInitBarrier
+func (b *Builder) InitBefore(barriers ...string) *Builder {
+	return b.With(WithInitBefore(barriers...))
+}
+
+// InitAfter initializes the service after the given init barriers, see WithInitBarrier
+func (b *Builder) InitAfter(barriers ...string) *Builder {
+	return b.With(WithInitAfter(barriers...))
+}
+
+// Phase sets the startup phase of the service, see Phaser
+func (b *Builder) Phase(p int) *Builder {
+	return b.With(WithPhase(p))
+}
+
+// Tags adds tags to the service, see Tagger
+func (b *Builder) Tags(tags ...string) *Builder {
+	return b.With(WithTags(tags...))
+}
+
+// DependsOn declares services that must be started before the service, see Dependent
+func (b *Builder) DependsOn(services ...string) *Builder {
+	return b.With(WithDependsOn(services...))
+}
+
+// Critical sets if a failing Run stops the container, default is true. See CriticalityReporter
+func (b *Builder) Critical(critical bool) *Builder {
+	return b.With(WithCritical(critical))
+}
+
+// StopTimeout limits the time the shutdown waits for the service, see WithStopTimeout
+func (b *Builder) StopTimeout(d time.Duration) *Builder {
+	return b.With(WithStopTimeout(d))
+}
+
+// RecoverPanics recovers panics in Init and Run of the service, see WithPanicRecovery
+func (b *Builder) RecoverPanics() *Builder {
+	return b.With(WithPanicRecovery())
+}
+
+// LockOSThread runs the service on a dedicated OS thread, see WithLockedOSThread
+func (b *Builder) LockOSThread() *Builder {
+	return b.With(WithLockedOSThread())
+}
+
+// AfterStop calls f after the service stopped, see WithAfterStop
+func (b *Builder) AfterStop(f func()) *Builder {
+	return b.With(WithAfterStop(f))
+}
+
+// FreeOSMemory returns memory to the operating system after the service stopped, see WithFreeOSMemory
+func (b *Builder) FreeOSMemory() *Builder {
+	return b.With(WithFreeOSMemory())
+}
+
+// RestartOnUnhealthy restarts the service when its health checks fail threshold times in a row,
+// see WithRestartOnUnhealthy
+func (b *Builder) RestartOnUnhealthy(threshold int, backoff Backoff) *Builder {
+	return b.With(WithRestartOnUnhealthy(threshold, backoff))
+}
+
+// StartupTimeout limits the time the service may take to become ready, see WithStartupTimeout
+func (b *Builder) StartupTimeout(d time.Duration) *Builder {
+	return b.With(WithStartupTimeout(d))
+}
+
+// Restart sets the restart policy of the service, see WithRestartPolicy
+func (b *Builder) Restart(policy RestartPolicy, backoff Backoff) *Builder {
+	return b.With(WithRestartPolicy(policy, backoff))
+}
+
+// With adds register options, they override the defaults of the container, see WithServiceDefaults
+func (b *Builder) With(opts ...RegisterOption) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
 func (b *Builder) Register(container *Container) {
-	container.Register(&genericService{b.name, b.init, b.run})
+	container.Register(b.build())
 }
 
 func (b *Builder) RegisterDefault() {
-	Default().Register(&genericService{b.name, b.init, b.run})
+	Default().Register(b.build())
+}
+
+// Build returns the service without registering it, e.g. to be returned by a Factory
+func (b *Builder) Build() Runner {
+	return b.build()
+}
+
+func (b *Builder) build() Runner {
+	s := &genericService{
+		name:   b.name,
+		init:   b.init,
+		run:    b.run,
+		health: b.health,
+		opts:   append([]RegisterOption{}, b.opts...),
+	}
+	if b.backgroundInit != nil {
+		return newBackgroundInitService(s, b.backgroundInit)
+	}
+	return s
 }
@@ -2,11 +2,15 @@ package service_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
 	"github.com/niondir/go-service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"testing"
-	"time"
 )
 
 func TestServiceBuilder(t *testing.T) {
@@ -52,3 +56,171 @@ func TestCtx(t *testing.T) {
 	<-parent.Done()
 	assert.Equal(t, context.DeadlineExceeded, parent.Err())
 }
+
+func TestBuilder_phaseAndDependsOn(t *testing.T) {
+	c := service.NewContainer()
+	var order []string
+	service.New("api").Init(initRecorder(&order, "api")).Phase(1).DependsOn("cache").Run(blockUntilDone).Register(c)
+	service.New("cache").Init(initRecorder(&order, "cache")).Phase(1).Run(blockUntilDone).Register(c)
+	service.New("db").Init(initRecorder(&order, "db")).Run(blockUntilDone).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Equal(t, []string{"db", "cache", "api"}, order)
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestBuilder_unknownDependency(t *testing.T) {
+	c := service.NewContainer()
+	service.New("api").DependsOn("db").Run(blockUntilDone).Register(c)
+
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown service 'db'")
+	c.WaitAllStopped(context.Background())
+}
+
+func TestBuilder_tags(t *testing.T) {
+	c := service.NewContainer()
+	service.New("worker").Tags("worker", "queue").Register(c)
+
+	assert.Equal(t, []string{"worker", "queue"}, c.Status()[0].Tags)
+}
+
+func TestBuilder_nonCritical(t *testing.T) {
+	c := service.NewContainer()
+	service.New("optional").Critical(false).Run(func(ctx context.Context) error {
+		return errors.New("optional failed")
+	}).Register(c)
+	service.New("main").Run(blockUntilDone).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, c.RunningCount(), "main must keep running")
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func blockUntilDone(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestBuilder_dependsOnWaitsForReady(t *testing.T) {
+	c := service.NewContainer()
+	db := ctxReadyService{newReadyService(50 * time.Millisecond)}
+	c.Register(db)
+	var dbReadyOnRun bool
+	service.New("api").DependsOn(fmt.Sprintf("%T", db)).Run(func(ctx context.Context) error {
+		select {
+		case <-db.ready:
+			dbReadyOnRun = true
+		default:
+		}
+		<-ctx.Done()
+		return nil
+	}).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	assert.True(t, dbReadyOnRun, "api must run after db is ready")
+}
+
+func TestBuilder_dependencyCycle(t *testing.T) {
+	c := service.NewContainer()
+	service.New("a").DependsOn("b").Register(c)
+	service.New("b").DependsOn("a").Register(c)
+
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cyclic")
+	c.WaitAllStopped(context.Background())
+}
+
+func TestBuilder_backgroundInit(t *testing.T) {
+	c := service.NewContainer()
+	initDone := make(chan struct{})
+	running := make(chan struct{})
+	service.New("cache").BackgroundInit(func(ctx context.Context) error {
+		<-initDone
+		return nil
+	}).Run(func(ctx context.Context) error {
+		close(running)
+		<-ctx.Done()
+		return nil
+	}).Tags("cache").Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	<-running
+
+	state, err := c.ServiceState("cache")
+	require.NoError(t, err)
+	assert.Equal(t, service.StateWarming, state)
+	assert.Equal(t, []string{"cache"}, c.Status()[0].Tags)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, c.WaitAllReady(ctx))
+
+	close(initDone)
+	require.NoError(t, c.WaitAllReady(context.Background()))
+	state, _ = c.ServiceState("cache")
+	assert.Equal(t, service.StateRunning, state)
+	assert.NoError(t, c.StopAllAndWait(context.Background()))
+}
+
+func TestBuilder_backgroundInitRestartedWhileRunning(t *testing.T) {
+	c := service.NewContainer()
+	release := make(chan struct{})
+	inits := atomic.Int32{}
+	service.New("cache").BackgroundInit(func(ctx context.Context) error {
+		inits.Add(1)
+		// Ignores ctx, the init of the first Run is still running after the restart
+		<-release
+		return nil
+	}).Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	require.Eventually(t, func() bool { return inits.Load() == 1 }, time.Second, time.Millisecond)
+
+	require.NoError(t, c.Restart(context.Background(), "cache"))
+	require.Eventually(t, func() bool { return inits.Load() == 2 }, time.Second, time.Millisecond)
+	close(release)
+
+	require.NoError(t, c.WaitAllReady(context.Background()))
+	assert.NoError(t, c.StopAllAndWait(context.Background()))
+}
+
+func TestBuilder_backgroundInitFails(t *testing.T) {
+	c := service.NewContainer()
+	initErr := errors.New("warmup failed")
+	service.New("cache").BackgroundInit(func(ctx context.Context) error {
+		return initErr
+	}).Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.ErrorIs(t, c.WaitAllStopped(context.Background()), initErr)
+}
+
+func TestBuilder_LockOSThread(t *testing.T) {
+	c := service.NewContainer()
+	runs := 0
+	service.New("gui").Run(func(ctx context.Context) error {
+		runs++
+		if runs < 2 {
+			return errors.New("restart")
+		}
+		<-ctx.Done()
+		return nil
+	}).LockOSThread().Restart(service.RestartOnFailure, service.Backoff{Initial: time.Millisecond}).Register(c)
+	service.New("worker").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	require.Eventually(t, func() bool {
+		return c.Status()[0].Restarts == 1
+	}, time.Second, time.Millisecond)
+	status := c.Status()
+	assert.True(t, status[0].LockedOSThread)
+	assert.False(t, status[1].LockedOSThread)
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+	assert.Equal(t, 2, runs)
+}
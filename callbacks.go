@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// shutdownCallback registered via OnShutdown or OnShutdownContext
+type shutdownCallback struct {
+	f func(ctx context.Context)
+	// site is the file and line where the callback was registered
+	site string
+}
+
+// WithShutdownCallbackWarnAfter configures after which duration a still running shutdown callback is logged as warning.
+// Default is 5 seconds, a duration <= 0 disables the warning.
+func WithShutdownCallbackWarnAfter(d time.Duration) Option {
+	return func(c *Container) {
+		c.callbackWarnAfter = d
+	}
+}
+
+// addShutdownCallback must be called directly from the exported OnShutdown* methods to detect the registration site
+func (c *Container) addShutdownCallback(f func(ctx context.Context)) {
+	site := "unknown"
+	if _, file, line, ok := runtime.Caller(2); ok {
+		site = fmt.Sprintf("%s:%d", file, line)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shutdownCallbacks = append(c.shutdownCallbacks, shutdownCallback{f: f, site: site})
+}
+
+func (c *Container) runShutdownCallback(ctx context.Context, index int, cb shutdownCallback) {
+	start := time.Now()
+	if c.callbackWarnAfter > 0 {
+		timer := time.AfterFunc(c.callbackWarnAfter, func() {
+			c.log.Warn("Shutdown callback is still running",
+				"index", index, "site", cb.site, "duration", time.Since(start), "container", c.name)
+		})
+		defer timer.Stop()
+	}
+	panicked := c.callSafe("shutdown callback", func() {
+		cb.f(ctx)
+	}, "index", index, "site", cb.site)
+	c.recordShutdown(func(r *ShutdownReport) {
+		r.Callbacks = append(r.Callbacks, CallbackTiming{Index: index, Site: cb.site, Start: start, Duration: time.Since(start), Panicked: panicked})
+	})
+	if c.callbackWarnAfter > 0 && time.Since(start) > c.callbackWarnAfter {
+		c.log.Warn("Shutdown callback was slow",
+			"index", index, "site", cb.site, "duration", time.Since(start), "container", c.name)
+	}
+}
+
+// callSafe executes a user provided callback and recovers from any panic inside the callback.
+// The panic is logged with stack trace, so a buggy callback can not break the container lifecycle.
+// Returns true if the callback panicked.
+func (c *Container) callSafe(what string, f func(), logArgs ...any) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			args := append([]any{"panic", r, "container", c.name, "stack", string(debug.Stack())}, logArgs...)
+			c.log.Error(fmt.Sprintf("Recovered panic in %s", what), args...)
+		}
+	}()
+	f()
+	return false
+}
@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownCallbackWarning(t *testing.T) {
+	buf := &bytes.Buffer{}
+	c := service.NewContainer(service.WithShutdownCallbackWarnAfter(10 * time.Millisecond))
+	c.SetLogger(slog.New(slog.NewTextHandler(buf, nil)))
+
+	var info service.RunInfo
+	c.OnShutdown(func() {})
+	c.OnShutdownContext(func(ctx context.Context) {
+		info, _ = service.RunInfoFromContext(ctx)
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	assert.Equal(t, c.RunID(), info.RunID)
+	assert.Contains(t, buf.String(), "Shutdown callback is still running")
+	assert.Contains(t, buf.String(), "index=1")
+	assert.Contains(t, buf.String(), "callbacks_test.go")
+}
+
+func TestShutdownCallbackPanic(t *testing.T) {
+	buf := &bytes.Buffer{}
+	c := service.NewContainer()
+	c.SetLogger(slog.New(slog.NewTextHandler(buf, nil)))
+
+	called := false
+	c.OnShutdown(func() {
+		panic("buggy callback")
+	})
+	c.OnShutdown(func() {
+		called = true
+	})
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	assert.True(t, called, "callbacks after a panic must still be called")
+	assert.Equal(t, 0, c.RunningCount())
+	assert.Contains(t, buf.String(), "Recovered panic in shutdown callback")
+	assert.Contains(t, buf.String(), "buggy callback")
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Capability is the name of an optional interface a service can implement, see Container.Capabilities
+type Capability string
+
+const (
+	CapabilityIniter              Capability = "Initer"
+	CapabilityReadyWaiter         Capability = "ReadyWaiter"
+	CapabilityShutdownPrioritizer Capability = "ShutdownPrioritizer"
+	CapabilityInitBarrierUser     Capability = "InitBarrierUser"
+	CapabilityReadier             Capability = "Readier"
+	CapabilityPhaser              Capability = "Phaser"
+	CapabilityTagger              Capability = "Tagger"
+	CapabilityDependent           Capability = "Dependent"
+	CapabilityCriticalityReporter Capability = "CriticalityReporter"
+	CapabilityHealthChecker       Capability = "HealthChecker"
+)
+
+// ErrMissingCapabilities is wrapped by the error of StartAll when services miss required capabilities,
+// see WithRequiredCapabilities
+var ErrMissingCapabilities = errors.New("missing required capabilities")
+
+// WithRequiredCapabilities fails StartAll before any service is initialized when a registered service
+// does not implement all of the given capabilities, e.g. to enforce that every service is health checked.
+// The deprecated ReadyWaiter satisfies CapabilityReadier.
+func WithRequiredCapabilities(caps ...Capability) Option {
+	return func(c *Container) {
+		c.requiredCapabilities = caps
+	}
+}
+
+// Capabilities returns the optional interfaces implemented by the service, e.g. CapabilityIniter.
+// For services registered with the Builder only the configured functions are reported, e.g. CapabilityIniter
+// only when Builder.Init was called. The capabilities are also reported with the ServiceStatus.
+// Returns an error if the service is not registered.
+func (c *Container) Capabilities(name string) ([]Capability, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := slices.IndexFunc(c.services, func(s *serviceInfo) bool { return s.name == name })
+	if i < 0 {
+		return nil, fmt.Errorf("service '%s' not registered in container '%s'", name, c.name)
+	}
+	return capabilitiesOf(c.services[i].service), nil
+}
+
+// capabilitiesOf returns the optional interfaces implemented by r in the order they are declared
+func capabilitiesOf(r Runner) []Capability {
+	service := r
+	if w, ok := r.(interface{ primary() Runner }); ok {
+		service = w.primary()
+	}
+	var caps []Capability
+	add := func(c Capability, ok bool) {
+		if ok {
+			caps = append(caps, c)
+		}
+	}
+	_, initer := service.(Initer)
+	_, readyWaiter := service.(ReadyWaiter)
+	_, readier := service.(Readier)
+	_, healthChecker := service.(HealthChecker)
+	if g, ok := service.(*genericService); ok {
+		initer = g.init != nil
+		healthChecker = g.health != nil
+	}
+	if _, ok := r.(*backgroundInitService); ok {
+		readier = true
+	}
+	_, prioritizer := service.(ShutdownPrioritizer)
+	_, barrierUser := service.(InitBarrierUser)
+	_, phaser := service.(Phaser)
+	_, tagger := service.(Tagger)
+	_, dependent := service.(Dependent)
+	_, criticality := service.(CriticalityReporter)
+
+	add(CapabilityIniter, initer)
+	add(CapabilityReadyWaiter, readyWaiter)
+	add(CapabilityShutdownPrioritizer, prioritizer)
+	add(CapabilityInitBarrierUser, barrierUser)
+	add(CapabilityReadier, readier)
+	add(CapabilityPhaser, phaser)
+	add(CapabilityTagger, tagger)
+	add(CapabilityDependent, dependent)
+	add(CapabilityCriticalityReporter, criticality)
+	add(CapabilityHealthChecker, healthChecker)
+	return caps
+}
+
+// verifyCapabilities returns an error listing all services that miss required capabilities
+func (c *Container) verifyCapabilities(services []*serviceInfo) error {
+	c.mu.Lock()
+	required := c.requiredCapabilities
+	c.mu.Unlock()
+	if len(required) == 0 {
+		return nil
+	}
+	var problems []string
+	for _, s := range services {
+		caps := capabilitiesOf(s.service)
+		if slices.Contains(caps, CapabilityReadyWaiter) {
+			caps = append(caps, CapabilityReadier)
+		}
+		var missing []string
+		for _, r := range required {
+			if !slices.Contains(caps, r) {
+				missing = append(missing, string(r))
+			}
+		}
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("'%s' misses %s", s.name, strings.Join(missing, ", ")))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingCapabilities, strings.Join(problems, "; "))
+	}
+	return nil
+}
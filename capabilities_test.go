@@ -0,0 +1,63 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type capableService struct{}
+
+func (s *capableService) Init(ctx context.Context) error   { return nil }
+func (s *capableService) Run(ctx context.Context) error    { return nil }
+func (s *capableService) Ready(ctx context.Context) error  { return nil }
+func (s *capableService) Health(ctx context.Context) error { return nil }
+func (s *capableService) Tags() []string                   { return []string{"http"} }
+func (s *capableService) String() string                   { return "capable" }
+
+func TestCapabilities(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(&capableService{})
+	service.New("builder").Init(func(ctx context.Context) error { return nil }).Run(blockUntilDone).Register(c)
+	service.New("plain").Run(blockUntilDone).Register(c)
+
+	caps, err := c.Capabilities("capable")
+	require.NoError(t, err)
+	assert.Equal(t, []service.Capability{service.CapabilityIniter, service.CapabilityReadier, service.CapabilityTagger, service.CapabilityHealthChecker}, caps)
+
+	caps, err = c.Capabilities("builder")
+	require.NoError(t, err)
+	assert.Equal(t, []service.Capability{service.CapabilityIniter}, caps)
+
+	caps, err = c.Capabilities("plain")
+	require.NoError(t, err)
+	assert.Empty(t, caps)
+
+	_, err = c.Capabilities("unknown")
+	assert.Error(t, err)
+
+	status := c.Status()
+	require.Len(t, status, 3)
+	assert.Equal(t, []service.Capability{service.CapabilityIniter, service.CapabilityReadier, service.CapabilityTagger, service.CapabilityHealthChecker}, status[0].Capabilities)
+}
+
+func TestWithRequiredCapabilities(t *testing.T) {
+	c := service.NewContainer(service.WithRequiredCapabilities(service.CapabilityReadier, service.CapabilityHealthChecker))
+	c.Register(&capableService{})
+	service.New("unchecked").Run(blockUntilDone).Register(c)
+	initCalled := false
+	service.New("checked").Init(func(ctx context.Context) error {
+		initCalled = true
+		return nil
+	}).Health(func(ctx context.Context) error { return nil }).Run(blockUntilDone).Register(c)
+
+	err := c.StartAll(context.Background())
+	require.ErrorIs(t, err, service.ErrMissingCapabilities)
+	assert.Contains(t, err.Error(), "'unchecked' misses Readier, HealthChecker")
+	assert.Contains(t, err.Error(), "'checked' misses Readier")
+	assert.False(t, initCalled)
+	_ = c.WaitAllStopped(context.Background())
+}
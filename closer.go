@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// managedCloser registered with ManageCloser
+type managedCloser struct {
+	name   string
+	closer io.Closer
+}
+
+// ManageCloser registers a resource, e.g. a database pool or file handle, that is opened before the services
+// are started and must be closed after all services stopped.
+// Resources are closed in reverse order of registration by WaitAllStopped, after the flushers, see RegisterFlusher.
+// Every resource is closed only once, register it again when the container is started again.
+// Errors of Close are logged and returned by WaitAllStopped as ServiceError of the resource, see Errors.
+func (c *Container) ManageCloser(name string, closer io.Closer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closers = append(c.closers, managedCloser{name: name, closer: closer})
+}
+
+// closeResources closes and removes all resources registered with ManageCloser in reverse order
+func (c *Container) closeResources() {
+	c.mu.Lock()
+	closers := c.closers
+	c.closers = nil
+	c.mu.Unlock()
+
+	for i := len(closers) - 1; i >= 0; i-- {
+		mc := closers[i]
+		start := time.Now()
+		var err error
+		if c.callSafe("closer", func() {
+			err = mc.closer.Close()
+		}, "resource", mc.name) {
+			err = errors.New("close panicked")
+		}
+		if err == nil {
+			c.log.Debug("Closed resource", "resource", mc.name, "duration", time.Since(start), "container", c.name)
+			continue
+		}
+		c.log.Error("Failed to close resource", "resource", mc.name, "error", err, "container", c.name)
+		c.addError(ServiceError{
+			Container: c.name,
+			Service:   mc.name,
+			Phase:     LifecycleShutdown,
+			Code:      CodeShutdownFailed,
+			Err:       err,
+			Time:      time.Now(),
+		})
+	}
+}
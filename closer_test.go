@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type closeRecorder struct {
+	mu     sync.Mutex
+	closed []string
+}
+
+func (r *closeRecorder) closer(name string, err error) closerFunc {
+	return func() error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.closed = append(r.closed, name)
+		return err
+	}
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func TestManageCloser(t *testing.T) {
+	r := &closeRecorder{}
+	c := service.NewContainer()
+	c.ManageCloser("db", r.closer("db", nil))
+	closeErr := errors.New("file busy")
+	c.ManageCloser("file", r.closer("file", closeErr))
+	service.New("worker").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		assert.Empty(t, r.closed, "resources must be closed after services stopped")
+		return nil
+	}).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	err := c.StopAllAndWait(context.Background())
+	assert.ErrorIs(t, err, closeErr)
+	var serviceErr service.ServiceError
+	require.ErrorAs(t, err, &serviceErr)
+	assert.Equal(t, "file", serviceErr.Service)
+	assert.Equal(t, service.LifecycleShutdown, serviceErr.Phase)
+	assert.Equal(t, []string{"file", "db"}, r.closed)
+
+	// Resources are only closed once
+	require.Error(t, c.WaitAllStopped(context.Background()))
+	assert.Equal(t, []string{"file", "db"}, r.closed)
+}
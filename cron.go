@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed cron expression, see ParseCronSchedule
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are set when the field is "*", see Next
+	domStar, dowStar bool
+	// every is set for "@every <duration>"
+	every time.Duration
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCronSchedule parses a cron expression with the five fields minute, hour, day of month, month and day of week,
+// e.g. "*/15 8-18 * * 1-5". Fields support "*", numbers, ranges "a-b", steps "/n" and lists "a,b".
+// Sunday is 0 or 7. When day of month and day of week are both restricted, a time matches either of them.
+// The descriptors @yearly, @monthly, @weekly, @daily, @hourly and "@every <duration>" are supported as well.
+func ParseCronSchedule(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := strings.CutPrefix(spec, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron schedule '%s': %w", spec, err)
+		}
+		if every <= 0 {
+			return nil, fmt.Errorf("invalid cron schedule '%s': duration must be positive", spec)
+		}
+		return &CronSchedule{every: every}, nil
+	}
+	if expr, ok := cronDescriptors[spec]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron schedule '%s': expected 5 fields, got %d", spec, len(fields))
+	}
+	s := &CronSchedule{
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}
+	bounds := []struct {
+		field    *uint64
+		min, max int
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dow, 0, 7},
+	}
+	for i, b := range bounds {
+		bits, err := parseCronField(fields[i], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron schedule '%s': %w", spec, err)
+		}
+		*b.field = bits
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseCronField returns a bit set with a bit for every value matched by the field
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		expr, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in '%s'", part)
+			}
+		}
+		lo, hi := min, max
+		if expr != "*" {
+			from, to, isRange := strings.Cut(expr, "-")
+			var err error
+			lo, err = strconv.Atoi(from)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value in '%s'", part)
+			}
+			hi = lo
+			if isRange {
+				hi, err = strconv.Atoi(to)
+				if err != nil {
+					return 0, fmt.Errorf("invalid range in '%s'", part)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("'%s' is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time after t that matches the schedule in the location of t.
+// Returns the zero time if no time matches within the next five years, e.g. for "0 0 31 2 *".
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// cronService runs a function on a schedule, see NewCron
+type cronService struct {
+	name     string
+	spec     string
+	schedule *CronSchedule
+	fn       func(ctx context.Context) error
+}
+
+// NewCron returns a service that calls fn at the times of the cron schedule until the context of Run is canceled,
+// see ParseCronSchedule. Runs do not overlap, a run that is due while fn is still running is skipped.
+// Errors and panics of fn are reported with ReportError and do not stop the service, see Container.Errors.
+// An invalid schedule is returned as error by Init.
+func NewCron(name, schedule string, fn func(ctx context.Context) error) Runner {
+	return &cronService{name: name, spec: schedule, fn: fn}
+}
+
+func (s *cronService) String() string {
+	return s.name
+}
+
+func (s *cronService) Init(ctx context.Context) error {
+	schedule, err := ParseCronSchedule(s.spec)
+	if err != nil {
+		return err
+	}
+	s.schedule = schedule
+	return nil
+}
+
+func (s *cronService) Run(ctx context.Context) error {
+	if s.schedule == nil {
+		if err := s.Init(ctx); err != nil {
+			return err
+		}
+	}
+	logger := LoggerFromContext(ctx)
+	for {
+		next := s.schedule.Next(time.Now())
+		if next.IsZero() {
+			logger.Warn("Cron schedule has no next run", "schedule", s.spec)
+			<-ctx.Done()
+			return nil
+		}
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil
+		case <-t.C:
+		}
+
+		start := time.Now()
+		if err := callIteration(ctx, s.fn); err != nil && ctx.Err() == nil {
+			ReportError(ctx, fmt.Errorf("cron run at %s failed: %w", next.Format(time.RFC3339), err))
+			continue
+		}
+		logger.Debug("Cron run done", "scheduled", next, "duration", time.Since(start))
+	}
+}
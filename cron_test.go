@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	// Monday
+	now := time.Date(2024, 1, 15, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 8-18 * * 1-5", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, 1, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, 1, 21, 12, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2024, 1, 15, 10, 9, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := service.ParseCronSchedule(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.next, s.Next(now))
+		})
+	}
+}
+
+func TestParseCronSchedule_invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every -1s", "@every x"} {
+		_, err := service.ParseCronSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestNewCron(t *testing.T) {
+	runs := atomic.Int32{}
+	jobErr := errors.New("job failed")
+	c := service.NewContainer()
+	c.Register(service.NewCron("cleanup", "@every 10ms", func(ctx context.Context) error {
+		if runs.Add(1) == 2 {
+			return jobErr
+		}
+		return nil
+	}))
+	require.NoError(t, c.StartAll(context.Background()))
+	require.Eventually(t, func() bool {
+		return runs.Load() >= 3
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, c.IsServiceRunning("cleanup"))
+
+	err := c.StopAllAndWait(context.Background())
+	assert.ErrorIs(t, err, jobErr)
+	errs := c.Errors()
+	require.Len(t, errs, 1)
+	assert.Equal(t, "cleanup", errs[0].Service)
+	assert.Equal(t, service.LifecycleRun, errs[0].Phase)
+	state, _ := c.ServiceState("cleanup")
+	assert.Equal(t, service.StateStopped, state)
+}
+
+func TestNewCron_invalidSchedule(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(service.NewCron("cleanup", "every day", func(ctx context.Context) error {
+		return nil
+	}))
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid cron schedule")
+}
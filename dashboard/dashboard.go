@@ -0,0 +1,139 @@
+// Package dashboard provides a service that renders the live state of a service.Container in the terminal.
+//
+// It is meant for development, similar to the output of "docker compose" for in-process services:
+//
+//	c := service.NewContainer()
+//	c.Register(dashboard.New(c, dashboard.WithEnabled(os.Getenv("DASHBOARD") != "")))
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/niondir/go-service"
+)
+
+var _ service.Runner = &Dashboard{}
+
+// clearScreen moves the cursor to the top left corner and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// Dashboard is a service that renders the status of all services inside a container
+type Dashboard struct {
+	container service.Introspector
+	out       io.Writer
+	enabled   bool
+	maxEvents int
+	// events are the most recent state changes, oldest first
+	events []event
+	// last known state per service, to detect state changes
+	last map[string]service.ServiceState
+}
+
+type event struct {
+	time  time.Time
+	name  string
+	state service.ServiceState
+	err   error
+}
+
+type Option func(d *Dashboard)
+
+// WithOutput sets the writer to render to, default is os.Stdout
+func WithOutput(w io.Writer) Option {
+	return func(d *Dashboard) {
+		d.out = w
+	}
+}
+
+// WithEnabled toggles the dashboard, a disabled dashboard does not render anything.
+// The dashboard is enabled by default.
+func WithEnabled(enabled bool) Option {
+	return func(d *Dashboard) {
+		d.enabled = enabled
+	}
+}
+
+// WithMaxEvents sets how many recent events are shown, default is 10
+func WithMaxEvents(n int) Option {
+	return func(d *Dashboard) {
+		d.maxEvents = n
+	}
+}
+
+// New creates a dashboard for the given container. The dashboard must be registered as service to be started.
+func New(c service.Introspector, opts ...Option) *Dashboard {
+	d := &Dashboard{
+		container: c,
+		out:       os.Stdout,
+		enabled:   true,
+		maxEvents: 10,
+		last:      map[string]service.ServiceState{},
+	}
+	for _, o := range opts {
+		o(d)
+	}
+	return d
+}
+
+func (d *Dashboard) String() string {
+	return "dashboard"
+}
+
+func (d *Dashboard) Run(ctx context.Context) error {
+	if !d.enabled {
+		<-ctx.Done()
+		return nil
+	}
+	for status := range d.container.WatchStatus(ctx) {
+		d.record(status)
+		err := d.render(status)
+		if err != nil {
+			return fmt.Errorf("failed to render dashboard: %w", err)
+		}
+	}
+	return nil
+}
+
+// record appends an event for every service that changed its state since the last snapshot
+func (d *Dashboard) record(status []service.ServiceStatus) {
+	now := time.Now()
+	for _, s := range status {
+		if last, ok := d.last[s.Name]; ok && last == s.State {
+			continue
+		}
+		d.last[s.Name] = s.State
+		d.events = append(d.events, event{time: now, name: s.Name, state: s.State, err: s.Err})
+	}
+	if len(d.events) > d.maxEvents {
+		d.events = d.events[len(d.events)-d.maxEvents:]
+	}
+}
+
+func (d *Dashboard) render(status []service.ServiceStatus) error {
+	w := tabwriter.NewWriter(d.out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, clearScreen)
+	fmt.Fprintf(w, "Container: %s\tRun: %s\n\n", d.container.Name(), d.container.RunID())
+	fmt.Fprintln(w, "SERVICE\tSTATE\tERROR")
+	for _, s := range status {
+		errText := ""
+		if s.Err != nil {
+			errText = s.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, s.State, errText)
+	}
+
+	fmt.Fprintln(w, "\nRECENT EVENTS")
+	for _, e := range d.events {
+		if e.err != nil {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.time.Format(time.TimeOnly), e.name, e.state, e.err)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t%s\t\n", e.time.Format(time.TimeOnly), e.name, e.state)
+		}
+	}
+	return w.Flush()
+}
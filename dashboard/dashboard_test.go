@@ -0,0 +1,70 @@
+package dashboard_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/niondir/go-service/dashboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDashboard(t *testing.T) {
+	out := &syncBuffer{}
+	c := service.NewContainer(service.WithName("app"))
+	c.Register(dashboard.New(c, dashboard.WithOutput(out)))
+	service.New("worker").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return errors.New("worker failed")
+	}).Register(c)
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+
+	workerRunning := regexp.MustCompile(`worker\s+Running`)
+	require.Eventually(t, func() bool {
+		return workerRunning.MatchString(out.String())
+	}, time.Second, 10*time.Millisecond)
+	assert.Contains(t, out.String(), "Container: app")
+	assert.Contains(t, out.String(), "RECENT EVENTS")
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestDashboard_disabled(t *testing.T) {
+	out := &syncBuffer{}
+	c := service.NewContainer()
+	c.Register(dashboard.New(c, dashboard.WithOutput(out), dashboard.WithEnabled(false)))
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	assert.Empty(t, out.String())
+}
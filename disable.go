@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// WithDisabledServices disables the services with the given names, see WithDisabledServicesEnv
+func WithDisabledServices(names ...string) Option {
+	return func(c *Container) {
+		c.disabledServices = append(c.disabledServices, names...)
+	}
+}
+
+// WithDisabledServicesEnv disables the services listed comma separated in the environment variable,
+// e.g. DISABLED_SERVICES=metrics,debug, to turn off a misbehaving service without a rebuild.
+// The variable is read by every StartAll. Disabled services and the services depending on them are not started,
+// the skip is logged and reported as warning in the StartReport. Unknown names are ignored.
+func WithDisabledServicesEnv(key string) Option {
+	return func(c *Container) {
+		c.disabledServicesEnv = key
+	}
+}
+
+// disabledNames returns the names of the disabled services, see WithDisabledServices and WithDisabledServicesEnv
+func (c *Container) disabledNames() []string {
+	c.mu.Lock()
+	disabled := slices.Clone(c.disabledServices)
+	key := c.disabledServicesEnv
+	c.mu.Unlock()
+	if key != "" {
+		for _, name := range strings.Split(os.Getenv(key), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				disabled = append(disabled, name)
+			}
+		}
+	}
+	return disabled
+}
+
+// skipDisabled removes the disabled services and the services depending on them.
+// Returns the remaining services and a reason for every skipped service.
+func skipDisabled(services []*serviceInfo, disabled []string) ([]*serviceInfo, map[string]string) {
+	skipped := map[string]string{}
+	if len(disabled) == 0 {
+		return services, skipped
+	}
+	for _, s := range services {
+		if slices.Contains(disabled, s.name) {
+			skipped[s.name] = "disabled"
+		}
+	}
+	// Skip dependents until no more services are affected
+	for changed := true; changed; {
+		changed = false
+		for _, s := range services {
+			if _, ok := skipped[s.name]; ok {
+				continue
+			}
+			for _, d := range s.dependsOn {
+				if _, ok := skipped[d]; ok {
+					skipped[s.name] = fmt.Sprintf("depends on skipped service '%s'", d)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return slices.DeleteFunc(slices.Clone(services), func(s *serviceInfo) bool {
+		_, ok := skipped[s.name]
+		return ok
+	}), skipped
+}
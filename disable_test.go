@@ -0,0 +1,49 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDisabledServicesEnv(t *testing.T) {
+	t.Setenv("DISABLED_SERVICES", "metrics, debug")
+	c := service.NewContainer(service.WithDisabledServicesEnv("DISABLED_SERVICES"))
+	service.New("api").Run(blockUntilDone).Register(c)
+	service.New("metrics").Run(blockUntilDone).Register(c)
+	service.New("exporter").Run(blockUntilDone).DependsOn("metrics").Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	assert.Equal(t, []string{"api"}, c.ServiceNames())
+	assert.Equal(t, []string{
+		"service 'exporter' not started: depends on skipped service 'metrics'",
+		"service 'metrics' not started: disabled",
+	}, c.StartReport().Warnings)
+
+	service.New("debug").Run(blockUntilDone).Register(c)
+	assert.False(t, c.IsServiceRunning("debug"))
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+
+	// The variable is read again on the next start
+	t.Setenv("DISABLED_SERVICES", "")
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Len(t, c.ServiceNames(), 4)
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
+
+func TestWithDisabledServices(t *testing.T) {
+	c := service.NewContainer(service.WithDisabledServices("debug"))
+	service.New("api").Run(blockUntilDone).Register(c)
+	service.New("debug").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Equal(t, []string{"api"}, c.ServiceNames())
+	state, err := c.ServiceState("debug")
+	require.NoError(t, err)
+	assert.Equal(t, service.StateRegistered, state)
+	assert.Equal(t, []string{"debug"}, c.StartReport().Skipped)
+	assert.True(t, c.Health(context.Background()).Healthy, "disabled services are ignored")
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Stop stops a single running service by name and waits until its Run method returned or ctx is done.
+// Other services keep running, even if the service returns an error. Stopping a service that is not running
+// is a no-op. The service stays registered and can be started again with Restart.
+func (c *Container) Stop(ctx context.Context, name string) error {
+	c.mu.Lock()
+	rc, ok := c.runContexts[name]
+	registered := slices.ContainsFunc(c.services, func(s *serviceInfo) bool { return s.name == name })
+	c.mu.Unlock()
+	if !registered {
+		return fmt.Errorf("service '%s' not registered in container '%s'", name, c.name)
+	}
+	if !ok {
+		return nil
+	}
+	c.serviceLogger(rc.service).Info("Stopping service by name")
+	return c.stopOne(ctx, name, false)
+}
+
+// WaitServiceStopped blocks until the Run method of the service returned or ctx is done, without stopping it.
+// Returns immediately when the service is not running. Returns an error if the service is not registered
+// or ctx is done before the service stopped.
+func (c *Container) WaitServiceStopped(ctx context.Context, name string) error {
+	c.mu.Lock()
+	rc, ok := c.runContexts[name]
+	registered := slices.ContainsFunc(c.services, func(s *serviceInfo) bool { return s.name == name })
+	c.mu.Unlock()
+	if !registered {
+		return fmt.Errorf("service '%s' not registered in container '%s'", name, c.name)
+	}
+	if !ok {
+		return nil
+	}
+	return c.waitRunContext(ctx, rc)
+}
+
+// waitRunContext blocks until Run of the service returned or ctx is done
+func (c *Container) waitRunContext(ctx context.Context, rc *runContext) error {
+	stopped := make(chan struct{})
+	go func() {
+		rc.wait(&c.mu)
+		close(stopped)
+	}()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for service '%s' to stop: %w", rc.service.name, ctx.Err())
+	case <-stopped:
+		return nil
+	}
+}
+
+// Restart stops a single service by name like Stop and starts it again, including Init.
+// The container must be running. Init is canceled when either ctx or the container is done.
+// The restart is counted in ServiceStatus.Restarts.
+func (c *Container) Restart(ctx context.Context, name string) error {
+	if err := c.Stop(ctx, name); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	idx := slices.IndexFunc(c.services, func(s *serviceInfo) bool { return s.name == name })
+	var info *serviceInfo
+	if idx >= 0 {
+		info = c.services[idx]
+		if rc, ok := c.runContexts[name]; ok {
+			// The restart count keeps increasing, e.g. for the restarts_total metric
+			c.carriedRestarts[name] = rc.restarts + 1
+		}
+		delete(c.runContexts, name)
+	}
+	c.mu.Unlock()
+	if info == nil {
+		return fmt.Errorf("service '%s' not registered in container '%s'", name, c.name)
+	}
+	c.serviceLogger(info).Info("Restarting service by name")
+	return c.startOne(ctx, info)
+}
+
+// StartOne registers a service in the already running container and starts it with the run context
+// of the container, e.g. for services that are created late. It blocks until Init returned and the
+// services it depends on are ready, see Dependent. Init is canceled when either ctx or the container is done.
+// On error the service stays registered in failed state, the other services keep running.
+func (c *Container) StartOne(ctx context.Context, service Runner, opts ...RegisterOption) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("container '%s' is not running", c.name)
+	}
+	info := newServiceInfo(service, c.serviceDefaults...)
+	for _, opt := range opts {
+		opt(info)
+	}
+	if err := c.addService(info); err != nil {
+		return err
+	}
+	return c.startOne(ctx, info)
+}
+
+// startOne initializes and runs a single service inside the already running container.
+// The service must already be registered. Init is canceled when either ctx or the container is done.
+func (c *Container) startOne(ctx context.Context, s *serviceInfo) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("container '%s' is not running", c.name)
+	}
+	c.mu.Lock()
+	shuttingDown := c.shuttingDown
+	c.mu.Unlock()
+	if shuttingDown {
+		return fmt.Errorf("container '%s' is shutting down", c.name)
+	}
+
+	initCtx, cancel := context.WithCancel(c.runCtx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	err := c.initOne(initCtx, s)
+	if err != nil {
+		return err
+	}
+	if err := c.waitDependencies(initCtx, s); err != nil {
+		c.mu.Lock()
+		rc := c.runContexts[s.name]
+		c.mu.Unlock()
+		c.setState(rc, StateStopped)
+		return err
+	}
+	return c.runOne(c.runCtx, s)
+}
+
+// stopOne stops a single service and waits until Run returned or ctx is done.
+// When remove is true the service is also unregistered from the container.
+func (c *Container) stopOne(ctx context.Context, name string, remove bool) error {
+	c.mu.Lock()
+	rc, ok := c.runContexts[name]
+	var cancel context.CancelFunc
+	if ok {
+		// The error of a service stopped on purpose must not stop the container
+		rc.stoppedByName = true
+		cancel = rc.cancel
+	}
+	c.mu.Unlock()
+	if ok {
+		if cancel != nil {
+			cancel()
+		}
+		if err := c.waitRunContext(ctx, rc); err != nil {
+			return err
+		}
+	}
+
+	if remove {
+		c.removeService(name)
+	}
+	return nil
+}
+
+// removeService unregisters a service that is not running
+func (c *Container) removeService(name string) {
+	c.mu.Lock()
+	delete(c.runContexts, name)
+	for i, s := range c.services {
+		if s.name == name {
+			c.services = append(c.services[:i:i], c.services[i+1:]...)
+			break
+		}
+	}
+	c.mu.Unlock()
+	c.notifyStatusChange()
+}
@@ -0,0 +1,95 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopAndRestart(t *testing.T) {
+	c := service.NewContainer()
+	var inits atomic.Int32
+	service.New("worker").Init(func(ctx context.Context) error {
+		inits.Add(1)
+		return nil
+	}).Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return errors.New("stopped with error")
+	}).Register(c)
+	service.New("api").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	require.NoError(t, c.Stop(context.Background(), "worker"))
+	assert.Equal(t, 1, c.RunningCount(), "other services keep running")
+	assert.Equal(t, service.StateFailed, c.Status()[0].State)
+	require.NoError(t, c.Stop(context.Background(), "worker"), "stopping twice is a no-op")
+
+	require.NoError(t, c.Restart(context.Background(), "worker"))
+	assert.Equal(t, 2, c.RunningCount())
+	assert.Equal(t, int32(2), inits.Load())
+
+	assert.Error(t, c.Stop(context.Background(), "unknown"))
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestRegisterAfterStartAll(t *testing.T) {
+	c := service.NewContainer()
+	service.New("db").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	service.New("late").Run(blockUntilDone).Register(c)
+	assert.Equal(t, 2, c.RunningCount())
+
+	initErr := errors.New("init failed")
+	err := c.StartOne(context.Background(), service.New("broken").Init(func(ctx context.Context) error {
+		return initErr
+	}).Build())
+	assert.ErrorIs(t, err, initErr)
+	assert.Equal(t, 2, c.RunningCount(), "other services keep running")
+
+	require.NoError(t, c.StartOne(context.Background(), service.New("api").DependsOn("db").Run(blockUntilDone).Build()))
+	assert.Equal(t, 3, c.RunningCount())
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestRegisterAfterStop(t *testing.T) {
+	c := service.NewContainer()
+	service.New("db").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+	buf := &bytes.Buffer{}
+	c.SetLogger(slog.New(slog.NewTextHandler(buf, nil)))
+
+	service.New("late").Run(blockUntilDone).Register(c)
+	assert.NotContains(t, buf.String(), "level=ERROR")
+	state, err := c.ServiceState("late")
+	require.NoError(t, err)
+	assert.Equal(t, service.StateRegistered, state)
+	assert.Empty(t, c.Errors())
+
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.True(t, c.IsServiceRunning("late"), "queued for the next start")
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
+
+func TestRestart_countsRestarts(t *testing.T) {
+	c := service.NewContainer()
+	service.New("api").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	require.NoError(t, c.Restart(context.Background(), "api"))
+	require.NoError(t, c.Restart(context.Background(), "api"))
+	assert.Equal(t, 2, c.Status()[0].Restarts)
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
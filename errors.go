@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LifecyclePhase is the part of the service lifecycle in which a ServiceError occurred
+type LifecyclePhase int
+
+const (
+	// LifecycleInit errors are returned by Init
+	LifecycleInit LifecyclePhase = iota
+	// LifecycleRun errors are returned by Run
+	LifecycleRun
+	// LifecycleShutdown errors occur while the service is stopped, e.g. when the stop timeout is exceeded
+	LifecycleShutdown
+)
+
+func (p LifecyclePhase) String() string {
+	switch p {
+	case LifecycleInit:
+		return "Init"
+	case LifecycleRun:
+		return "Run"
+	case LifecycleShutdown:
+		return "Shutdown"
+	default:
+		return "Unknown"
+	}
+}
+
+// ErrorCode is a stable code of a lifecycle failure, e.g. to key alerting rules off it, see ErrorCodeOf
+type ErrorCode string
+
+const (
+	CodeInitFailed     ErrorCode = "INIT_FAILED"
+	CodeRunFailed      ErrorCode = "RUN_FAILED"
+	CodeShutdownFailed ErrorCode = "SHUTDOWN_FAILED"
+	CodeStopTimeout    ErrorCode = "STOP_TIMEOUT"
+	CodePanic          ErrorCode = "PANIC"
+	CodeCrashLoop      ErrorCode = "CRASH_LOOP"
+	CodeUnhealthy      ErrorCode = "UNHEALTHY"
+)
+
+// ErrStopTimeout is wrapped by the error of a service that did not stop within its stop timeout, see WithStopTimeout
+var ErrStopTimeout = errors.New("stop timeout exceeded")
+
+// ErrCrashLoop is wrapped by the error of a service that exceeded the max restarts of its restart policy,
+// see WithRestartPolicy
+var ErrCrashLoop = errors.New("crash loop")
+
+// ErrorCodeOf returns the code of an error that occurred in the given phase.
+// Panics, crash loops, stop timeouts and failed health checks are detected by the wrapped errors, see PanicError,
+// ErrCrashLoop, ErrRestartIntensity, ErrStopTimeout and ErrUnhealthy. Other errors are coded by the phase.
+func ErrorCodeOf(phase LifecyclePhase, err error) ErrorCode {
+	var panicErr *PanicError
+	switch {
+	case errors.As(err, &panicErr):
+		return CodePanic
+	case errors.Is(err, ErrCrashLoop) || errors.Is(err, ErrRestartIntensity):
+		return CodeCrashLoop
+	case errors.Is(err, ErrStopTimeout):
+		return CodeStopTimeout
+	case errors.Is(err, ErrUnhealthy):
+		return CodeUnhealthy
+	}
+	switch phase {
+	case LifecycleInit:
+		return CodeInitFailed
+	case LifecycleShutdown:
+		return CodeShutdownFailed
+	default:
+		return CodeRunFailed
+	}
+}
+
+// ServiceError is an error that occurred in a service, see Container.Errors.
+// The original error is wrapped and can be checked with errors.Is and errors.As.
+type ServiceError struct {
+	Container string
+	Service   string
+	Phase     LifecyclePhase
+	// Code of the error, see ErrorCodeOf
+	Code ErrorCode
+	Err  error
+	Time time.Time
+}
+
+func (e ServiceError) Error() string {
+	return fmt.Sprintf("%s/%s: %s failed: %v", e.Container, e.Service, e.Phase, e.Err)
+}
+
+func (e ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// Errors returns all errors that occurred in services during the current run in the order they occurred.
+// Unlike ServiceErrors, errors of Init and of the shutdown are included.
+func (c *Container) Errors() []ServiceError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ServiceError{}, c.serviceErrors...)
+}
+
+// recordError adds an error to the list returned by Errors
+func (c *Container) recordError(s *serviceInfo, phase LifecyclePhase, err error) {
+	c.addError(ServiceError{
+		Container: c.name,
+		Service:   s.name,
+		Phase:     phase,
+		Code:      ErrorCodeOf(phase, err),
+		Err:       err,
+		Time:      time.Now(),
+	})
+}
+
+// addError adds the error to the list returned by Errors, writes it to the error sink and raises an alert for it,
+// see WithErrorSink and WithAlerter
+func (c *Container) addError(e ServiceError) {
+	c.mu.Lock()
+	c.serviceErrors = append(c.serviceErrors, e)
+	c.mu.Unlock()
+	c.writeToSink(e)
+	c.alertError(e)
+}
+
+type errorReporterKey struct{}
+
+// ReportError records an error of the service in ctx without stopping it, e.g. for failed jobs of a long-running
+// service, see Container.Errors. The error is recorded for LifecycleRun and logged.
+// ctx must be the context of Init or Run or derived from it, otherwise the error is only logged.
+func ReportError(ctx context.Context, err error) {
+	LoggerFromContext(ctx).Error("Service reported error", "error", err)
+	if report, ok := ctx.Value(errorReporterKey{}).(func(err error)); ok {
+		report(err)
+	}
+}
+
+// withErrorReporter adds the error reporter of the service to ctx, see ReportError
+func (c *Container) withErrorReporter(ctx context.Context, s *serviceInfo) context.Context {
+	return context.WithValue(ctx, errorReporterKey{}, func(err error) {
+		c.recordError(s, LifecycleRun, err)
+	})
+}
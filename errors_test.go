@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	c := service.NewContainer(service.WithName("app"))
+	runErr := errors.New("crashed")
+	service.New("failing").Run(func(ctx context.Context) error {
+		return runErr
+	}).Register(c)
+	service.New("slow").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}).StopTimeout(10 * time.Millisecond).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	c.WaitAllStopped(context.Background())
+
+	errs := c.Errors()
+	require.Len(t, errs, 2)
+	assert.Equal(t, "app", errs[0].Container)
+	assert.Equal(t, "failing", errs[0].Service)
+	assert.Equal(t, service.LifecycleRun, errs[0].Phase)
+	assert.ErrorIs(t, errs[0], runErr)
+	assert.False(t, errs[0].Time.IsZero())
+	assert.Equal(t, service.CodeRunFailed, errs[0].Code)
+	assert.Equal(t, "slow", errs[1].Service)
+	assert.Equal(t, service.LifecycleShutdown, errs[1].Phase)
+	assert.Equal(t, service.CodeStopTimeout, errs[1].Code)
+	assert.ErrorIs(t, errs[1], service.ErrStopTimeout)
+}
+
+func TestErrors_init(t *testing.T) {
+	c := service.NewContainer()
+	initErr := errors.New("init failed")
+	service.New("failing").Init(func(ctx context.Context) error {
+		return initErr
+	}).Run(blockUntilDone).Register(c)
+	require.Error(t, c.StartAll(context.Background()))
+	c.WaitAllStopped(context.Background())
+
+	errs := c.Errors()
+	require.Len(t, errs, 1)
+	assert.Equal(t, service.LifecycleInit, errs[0].Phase)
+	assert.Equal(t, service.CodeInitFailed, errs[0].Code)
+	var serviceErr service.ServiceError
+	require.ErrorAs(t, errs[0], &serviceErr)
+	assert.ErrorIs(t, serviceErr, initErr)
+	assert.Empty(t, c.ServiceErrors())
+}
+
+func TestWaitAllStopped_error(t *testing.T) {
+	c := service.NewContainer()
+	runErr := errors.New("crashed")
+	service.New("failing").Run(func(ctx context.Context) error {
+		return runErr
+	}).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	err := c.WaitAllStopped(context.Background())
+	assert.ErrorIs(t, err, runErr)
+
+	c = service.NewContainer()
+	service.New("worker").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, c.WaitAllStopped(ctx), context.DeadlineExceeded)
+
+	c.StopAll()
+	assert.NoError(t, c.WaitAllStopped(context.Background()))
+}
+
+func TestStopAllAndWait(t *testing.T) {
+	c := service.NewContainer()
+	stopErr := errors.New("failed to flush")
+	service.New("worker").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return stopErr
+	}).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	err := c.StopAllAndWait(context.Background())
+	assert.ErrorIs(t, err, stopErr)
+	assert.Equal(t, service.ContainerFailed, c.State())
+}
+
+func TestErrorCodeOf(t *testing.T) {
+	err := errors.New("failed")
+	assert.Equal(t, service.CodeInitFailed, service.ErrorCodeOf(service.LifecycleInit, err))
+	assert.Equal(t, service.CodeRunFailed, service.ErrorCodeOf(service.LifecycleRun, err))
+	assert.Equal(t, service.CodeShutdownFailed, service.ErrorCodeOf(service.LifecycleShutdown, err))
+	assert.Equal(t, service.CodePanic, service.ErrorCodeOf(service.LifecycleInit, fmt.Errorf("wrapped: %w", &service.PanicError{Value: "boom"})))
+	assert.Equal(t, service.CodeCrashLoop, service.ErrorCodeOf(service.LifecycleRun, fmt.Errorf("%w: %w", service.ErrCrashLoop, err)))
+	assert.Equal(t, service.CodeCrashLoop, service.ErrorCodeOf(service.LifecycleRun, service.ErrRestartIntensity))
+	assert.Equal(t, service.CodeStopTimeout, service.ErrorCodeOf(service.LifecycleShutdown, service.ErrStopTimeout))
+}
+
+func TestErrors_crashLoop(t *testing.T) {
+	c := service.NewContainer()
+	runErr := errors.New("crashed")
+	service.New("flaky").Run(func(ctx context.Context) error {
+		return runErr
+	}).Restart(service.RestartOnFailure, service.Backoff{Initial: time.Millisecond, MaxRestarts: 2}).Register(c)
+	events := c.Events(context.Background())
+	require.NoError(t, c.StartAll(context.Background()))
+	err := c.WaitAllStopped(context.Background())
+	assert.ErrorIs(t, err, service.ErrCrashLoop)
+	assert.ErrorIs(t, err, runErr)
+
+	errs := c.Errors()
+	require.Len(t, errs, 1)
+	assert.Equal(t, service.CodeCrashLoop, errs[0].Code)
+	assert.Equal(t, service.CodeCrashLoop, c.Status()[0].ErrCode)
+	for e := range events {
+		if e.Type == service.EventRestarting {
+			assert.Equal(t, service.CodeRunFailed, e.Code)
+		}
+		if e.Type == service.EventFailed {
+			assert.Equal(t, service.CodeCrashLoop, e.Code)
+			break
+		}
+	}
+}
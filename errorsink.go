@@ -0,0 +1,96 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrorSink persists service errors, e.g. for environments without centralized logging, see WithErrorSink
+type ErrorSink interface {
+	// WriteError is called for every error added to Container.Errors, it must be safe for concurrent use
+	WriteError(e ServiceError) error
+}
+
+// WithErrorSink writes every service error to the sink, including recovered panics, see Container.Errors.
+// Errors of the sink are logged.
+func WithErrorSink(sink ErrorSink) Option {
+	return func(c *Container) {
+		c.errorSink = sink
+	}
+}
+
+// writeToSink writes the error to the error sink if any, see WithErrorSink
+func (c *Container) writeToSink(e ServiceError) {
+	if c.errorSink == nil {
+		return
+	}
+	if err := c.errorSink.WriteError(e); err != nil {
+		c.log.Error("Failed to write error to sink", "error", err, "name", e.Service, "container", c.name)
+	}
+}
+
+var _ ErrorSink = &FileErrorSink{}
+
+// FileErrorSink appends service errors as JSON lines to a file, see NewFileErrorSink
+type FileErrorSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// fileErrorRecord is a single line written by FileErrorSink
+type fileErrorRecord struct {
+	Time      time.Time `json:"time"`
+	Container string    `json:"container"`
+	Service   string    `json:"service"`
+	Phase     string    `json:"phase"`
+	Code      ErrorCode `json:"code"`
+	Error     string    `json:"error"`
+	// Stack of recovered panics, see PanicError
+	Stack string `json:"stack,omitempty"`
+}
+
+// NewFileErrorSink opens or creates the file at path for appending service errors as JSON lines.
+// Every error is synced to disk, so it survives the exit of the process. Close the sink when it is no longer used.
+func NewFileErrorSink(path string) (*FileErrorSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open error sink: %w", err)
+	}
+	return &FileErrorSink{file: f}, nil
+}
+
+func (s *FileErrorSink) WriteError(e ServiceError) error {
+	record := fileErrorRecord{
+		Time:      e.Time,
+		Container: e.Container,
+		Service:   e.Service,
+		Phase:     e.Phase.String(),
+		Code:      e.Code,
+		Error:     e.Err.Error(),
+	}
+	var panicErr *PanicError
+	if errors.As(e.Err, &panicErr) {
+		record.Stack = string(panicErr.Stack)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close closes the file
+func (s *FileErrorSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,52 @@
+package service_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileErrorSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	sink, err := service.NewFileErrorSink(path)
+	require.NoError(t, err)
+
+	c := service.NewContainer(service.WithName("app"), service.WithErrorSink(sink))
+	service.New("job").Run(func(ctx context.Context) error {
+		return errors.New("job failed")
+	}).Critical(false).Register(c)
+	service.New("buggy").Run(func(ctx context.Context) error {
+		panic("nil map")
+	}).RecoverPanics().Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	require.Error(t, c.WaitAllStopped(context.Background()))
+	require.NoError(t, sink.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	records := map[string]map[string]any{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		records[r["service"].(string)] = r
+	}
+	require.Len(t, records, 2)
+	assert.Equal(t, "app", records["job"]["container"])
+	assert.Equal(t, "Run", records["job"]["phase"])
+	assert.Equal(t, "RUN_FAILED", records["job"]["code"])
+	assert.Equal(t, "job failed", records["job"]["error"])
+	assert.NotEmpty(t, records["job"]["time"])
+	assert.NotContains(t, records["job"], "stack")
+	assert.Equal(t, "PANIC", records["buggy"]["code"])
+	assert.Contains(t, records["buggy"]["stack"], "goroutine")
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// EventType is the kind of lifecycle Event
+type EventType int
+
+const (
+	// EventRegistered is emitted when a service was registered
+	EventRegistered EventType = iota
+	// EventInitializing is emitted before Init is called
+	EventInitializing
+	// EventRunning is emitted before Run is called
+	EventRunning
+	// EventRestarting is emitted when a service is restarted by its restart policy, see WithRestartPolicy
+	EventRestarting
+	// EventStopped is emitted when Run returned without error or the service was never run
+	EventStopped
+	// EventFailed is emitted when Init or Run returned an error
+	EventFailed
+	// EventAbandoned is emitted when a service that did not stop was abandoned, see Container.Abandon
+	EventAbandoned
+	// EventStopping is emitted when a running service is canceled
+	EventStopping
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventRegistered:
+		return "Registered"
+	case EventInitializing:
+		return "Initializing"
+	case EventRunning:
+		return "Running"
+	case EventRestarting:
+		return "Restarting"
+	case EventStopped:
+		return "Stopped"
+	case EventFailed:
+		return "Failed"
+	case EventAbandoned:
+		return "Abandoned"
+	case EventStopping:
+		return "Stopping"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event describes a single lifecycle change of a service, see Container.Subscribe
+type Event struct {
+	Type      EventType
+	Container string
+	Service   string
+	Time      time.Time
+	// Err returned by Init or Run for EventFailed and EventRestarting
+	Err error
+	// Code of Err, empty without error, see ErrorCodeOf
+	Code ErrorCode
+}
+
+// eventSubscriber delivers events to a single subscriber in order, without blocking the emitter
+type eventSubscriber struct {
+	f       func(e Event)
+	mu      sync.Mutex
+	queue   []Event
+	signal  chan struct{}
+	done    chan struct{}
+	onClose func()
+}
+
+// Subscribe calls f for every lifecycle event of the services in the container until unsubscribe is called.
+// Events are delivered in order from a separate goroutine, a slow subscriber does not block the container.
+func (c *Container) Subscribe(f func(e Event)) (unsubscribe func()) {
+	return c.subscribe(f, nil)
+}
+
+// Events returns a channel emitting all lifecycle events of the services in the container.
+// The channel is closed when ctx is done. Events are buffered for the receiver in order.
+func (c *Container) Events(ctx context.Context) <-chan Event {
+	out := make(chan Event)
+	unsubscribe := c.subscribe(func(e Event) {
+		select {
+		case out <- e:
+		case <-ctx.Done():
+		}
+	}, func() {
+		close(out)
+	})
+	context.AfterFunc(ctx, unsubscribe)
+	return out
+}
+
+func (c *Container) subscribe(f func(e Event), onClose func()) (unsubscribe func()) {
+	sub := &eventSubscriber{
+		f:       f,
+		signal:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		onClose: onClose,
+	}
+	c.eventMu.Lock()
+	c.eventSubscribers[sub] = struct{}{}
+	c.eventMu.Unlock()
+	go c.deliverEvents(sub)
+
+	once := sync.Once{}
+	return func() {
+		once.Do(func() {
+			c.eventMu.Lock()
+			delete(c.eventSubscribers, sub)
+			c.eventMu.Unlock()
+			close(sub.done)
+		})
+	}
+}
+
+func (c *Container) deliverEvents(sub *eventSubscriber) {
+	if sub.onClose != nil {
+		defer sub.onClose()
+	}
+	for {
+		select {
+		case <-sub.done:
+			return
+		case <-sub.signal:
+		}
+		for {
+			sub.mu.Lock()
+			if len(sub.queue) == 0 {
+				sub.mu.Unlock()
+				break
+			}
+			e := sub.queue[0]
+			sub.queue = sub.queue[1:]
+			sub.mu.Unlock()
+			select {
+			case <-sub.done:
+				return
+			default:
+			}
+			c.callSafe("event subscriber", func() {
+				sub.f(e)
+			}, "event", e.Type, "name", e.Service)
+		}
+	}
+}
+
+// emitEvent queues the event for all subscribers, errors are coded as errors of Run
+func (c *Container) emitEvent(t EventType, service string, err error) {
+	c.emitPhaseEvent(t, service, LifecycleRun, err)
+}
+
+// emitPhaseEvent is like emitEvent, the error is coded as error of the given phase
+func (c *Container) emitPhaseEvent(t EventType, service string, phase LifecyclePhase, err error) {
+	e := Event{Type: t, Container: c.name, Service: service, Time: time.Now(), Err: err}
+	if err != nil {
+		e.Code = ErrorCodeOf(phase, err)
+	}
+	c.eventMu.Lock()
+	defer c.eventMu.Unlock()
+	for sub := range c.eventSubscribers {
+		sub.mu.Lock()
+		sub.queue = append(sub.queue, e)
+		sub.mu.Unlock()
+		select {
+		case sub.signal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// eventTypeOf returns the event emitted when a service enters the state
+func eventTypeOf(state ServiceState) EventType {
+	switch state {
+	case StateInitializing:
+		return EventInitializing
+	case StateRunning:
+		return EventRunning
+	case StateStopped:
+		return EventStopped
+	case StateFailed:
+		return EventFailed
+	case StateAbandoned:
+		return EventAbandoned
+	case StateStopping:
+		return EventStopping
+	default:
+		return EventRegistered
+	}
+}
@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents(t *testing.T) {
+	c := service.NewContainer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := c.Events(ctx)
+
+	runErr := errors.New("failed")
+	service.New("worker").Run(func(ctx context.Context) error {
+		return runErr
+	}).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	c.WaitAllStopped(context.Background())
+
+	var types []service.EventType
+	var last service.Event
+	for len(types) < 4 {
+		select {
+		case e := <-events:
+			assert.Equal(t, "worker", e.Service)
+			types = append(types, e.Type)
+			last = e
+		case <-time.After(time.Second):
+			t.Fatalf("missing events, got %v", types)
+		}
+	}
+	assert.Equal(t, []service.EventType{service.EventRegistered, service.EventInitializing, service.EventRunning, service.EventFailed}, types)
+	assert.ErrorIs(t, last.Err, runErr)
+
+	cancel()
+	require.Eventually(t, func() bool {
+		_, ok := <-events
+		return !ok
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestSubscribe_unsubscribe(t *testing.T) {
+	c := service.NewContainer()
+	received := make(chan service.Event, 10)
+	unsubscribe := c.Subscribe(func(e service.Event) {
+		received <- e
+	})
+	service.New("a").Register(c)
+	e := <-received
+	assert.Equal(t, service.EventRegistered, e.Type)
+
+	unsubscribe()
+	service.New("b").Register(c)
+	select {
+	case e := <-received:
+		t.Fatalf("unexpected event after unsubscribe: %v", e)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
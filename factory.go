@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Factory creates the service for a single key of a service family, see Container.RegisterFactory
+type Factory func(key string) Runner
+
+// FactoryOption configures a service family, see RegisterFactory
+type FactoryOption func(f *serviceFamily)
+
+// WithMaxInstances limits the number of instances of a service family.
+// When the limit is reached, the least recently used instance is stopped before a new one is created.
+func WithMaxInstances(n int) FactoryOption {
+	return func(f *serviceFamily) {
+		f.maxInstances = n
+	}
+}
+
+// WithIdleTimeout stops instances that were not used for the given duration.
+// An instance is used when EnsureInstance is called for its key.
+func WithIdleTimeout(d time.Duration) FactoryOption {
+	return func(f *serviceFamily) {
+		f.idleTimeout = d
+	}
+}
+
+// serviceFamily is a dynamic group of services created by the same factory
+type serviceFamily struct {
+	prefix       string
+	factory      Factory
+	opts         []FactoryOption
+	maxInstances int
+	idleTimeout  time.Duration
+	// mu serializes creating and stopping instances of the family
+	mu sync.Mutex
+	// keys of all instances with the time they were last used
+	keys map[string]time.Time
+	// evictorStarted is set when the idle evictor is running
+	evictorStarted bool
+}
+
+// InstanceName returns the service name of the instance with the given key of a service family
+func InstanceName(prefix string, key string) string {
+	return prefix + ":" + key
+}
+
+// RegisterFactory registers a family of services that are created on demand, one instance per key,
+// e.g. one worker per tenant, queue or shard. Instances are started with EnsureInstance and stopped with StopInstance.
+// Instances are named by InstanceName and are part of the container like any other service.
+func (c *Container) RegisterFactory(prefix string, factory Factory, opts ...FactoryOption) {
+	f := &serviceFamily{
+		prefix:  prefix,
+		factory: factory,
+		opts:    opts,
+		keys:    map[string]time.Time{},
+	}
+	for _, o := range opts {
+		o(f)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.families[prefix]; ok {
+		panic(fmt.Sprintf("Factory '%s' already registered in container %s", prefix, c.name))
+	}
+	c.families[prefix] = f
+}
+
+func (c *Container) family(prefix string) (*serviceFamily, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.families[prefix]
+	if !ok {
+		return nil, fmt.Errorf("no factory '%s' registered in container '%s'", prefix, c.name)
+	}
+	return f, nil
+}
+
+// EnsureInstance makes sure the instance for key is running. If it does not exist yet, it is created
+// by the factory, initialized and started inside the running container.
+// The ctx limits the time spent in Init of a new instance.
+func (c *Container) EnsureInstance(ctx context.Context, prefix string, key string) error {
+	f, err := c.family(prefix)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := InstanceName(prefix, key)
+	if _, ok := f.keys[key]; ok {
+		if c.IsServiceRunning(name) {
+			f.keys[key] = time.Now()
+			return nil
+		}
+		// The instance returned from Run, replace it with a new one
+		c.removeService(name)
+		delete(f.keys, key)
+	}
+
+	if f.maxInstances > 0 && len(f.keys) >= f.maxInstances {
+		err = c.evictLeastRecentlyUsed(f)
+		if err != nil {
+			return err
+		}
+	}
+
+	runner := f.factory(key)
+	if runner == nil {
+		return fmt.Errorf("factory '%s' returned no service for key '%s'", prefix, key)
+	}
+	info := newServiceInfo(runner, c.serviceDefaults...)
+	info.name = name
+	info.family = prefix
+	err = c.addService(info)
+	if err != nil {
+		return err
+	}
+	err = c.startOne(ctx, info)
+	if err != nil {
+		c.removeService(info.name)
+		return err
+	}
+	f.keys[key] = time.Now()
+	if f.idleTimeout > 0 && !f.evictorStarted {
+		f.evictorStarted = true
+		go c.evictIdle(f)
+	}
+	return nil
+}
+
+// StopInstance stops the instance for key and removes it from the container.
+// It blocks until the Run method of the instance returned.
+func (c *Container) StopInstance(prefix string, key string) error {
+	f, err := c.family(prefix)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.keys[key]; !ok {
+		return fmt.Errorf("no instance '%s' running in container '%s'", InstanceName(prefix, key), c.name)
+	}
+	return c.stopInstance(f, key)
+}
+
+// stopInstance must be called while holding f.mu
+func (c *Container) stopInstance(f *serviceFamily, key string) error {
+	err := c.stopOne(context.Background(), InstanceName(f.prefix, key), true)
+	if err != nil {
+		return err
+	}
+	delete(f.keys, key)
+	return nil
+}
+
+// evictLeastRecentlyUsed must be called while holding f.mu
+func (c *Container) evictLeastRecentlyUsed(f *serviceFamily) error {
+	lruKey := ""
+	var lruTime time.Time
+	for k, used := range f.keys {
+		if lruKey == "" || used.Before(lruTime) {
+			lruKey = k
+			lruTime = used
+		}
+	}
+	c.log.Info("Evicting least recently used instance", "name", InstanceName(f.prefix, lruKey), "container", c.name)
+	return c.stopInstance(f, lruKey)
+}
+
+// evictIdle periodically stops instances that were idle for longer than the idle timeout until the container stops
+func (c *Container) evictIdle(f *serviceFamily) {
+	c.mu.Lock()
+	runCtx := c.runCtx
+	c.mu.Unlock()
+	ticker := time.NewTicker(f.idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-runCtx.Done():
+			return
+		case <-ticker.C:
+		}
+		f.mu.Lock()
+		for k, used := range f.keys {
+			if time.Since(used) < f.idleTimeout {
+				continue
+			}
+			c.log.Info("Evicting idle instance", "name", InstanceName(f.prefix, k), "container", c.name)
+			err := c.stopInstance(f, k)
+			if err != nil {
+				c.log.Error("Failed to evict idle instance", "name", InstanceName(f.prefix, k), "error", err)
+			}
+		}
+		f.mu.Unlock()
+	}
+}
+
+// Instances returns the sorted keys of all instances of a service family
+func (c *Container) Instances(prefix string) []string {
+	f, err := c.family(prefix)
+	if err != nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]string, 0, len(f.keys))
+	for k := range f.keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFactory(t *testing.T) {
+	c := service.NewContainer()
+	running := atomic.Int32{}
+	c.RegisterFactory("tenant", func(key string) service.Runner {
+		return service.New("worker-" + key).Run(func(ctx context.Context) error {
+			running.Add(1)
+			defer running.Add(-1)
+			<-ctx.Done()
+			return nil
+		}).Build()
+	})
+
+	ctx := context.Background()
+	err := c.EnsureInstance(ctx, "tenant", "a")
+	require.Error(t, err, "container is not running")
+
+	err = c.StartAll(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, c.EnsureInstance(ctx, "tenant", "a"))
+	require.NoError(t, c.EnsureInstance(ctx, "tenant", "b"))
+	require.NoError(t, c.EnsureInstance(ctx, "tenant", "a"))
+	assert.Equal(t, []string{"a", "b"}, c.Instances("tenant"))
+	assert.ElementsMatch(t, []string{"tenant:a", "tenant:b"}, c.ServiceNames())
+	assert.Eventually(t, func() bool { return running.Load() == 2 }, time.Second, time.Millisecond)
+
+	require.NoError(t, c.StopInstance("tenant", "a"))
+	assert.Equal(t, []string{"b"}, c.Instances("tenant"))
+	assert.Equal(t, int32(1), running.Load())
+	assert.Error(t, c.StopInstance("tenant", "a"))
+	assert.Error(t, c.EnsureInstance(ctx, "unknown", "a"))
+
+	c.StopAll()
+	c.WaitAllStopped(ctx)
+	assert.Equal(t, int32(0), running.Load())
+	assert.Len(t, c.ServiceErrors(), 0)
+}
+
+func newBlockingRunner(key string) service.Runner {
+	return service.New(key).Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}).Build()
+}
+
+func TestRegisterFactory_maxInstances(t *testing.T) {
+	c := service.NewContainer()
+	c.RegisterFactory("tenant", newBlockingRunner, service.WithMaxInstances(2))
+
+	ctx := context.Background()
+	require.NoError(t, c.StartAll(ctx))
+
+	require.NoError(t, c.EnsureInstance(ctx, "tenant", "a"))
+	require.NoError(t, c.EnsureInstance(ctx, "tenant", "b"))
+	// Use "a", so "b" is the least recently used
+	require.NoError(t, c.EnsureInstance(ctx, "tenant", "a"))
+	require.NoError(t, c.EnsureInstance(ctx, "tenant", "c"))
+	assert.Equal(t, []string{"a", "c"}, c.Instances("tenant"))
+
+	c.StopAll()
+	c.WaitAllStopped(ctx)
+}
+
+func TestRegisterFactory_idleTimeout(t *testing.T) {
+	c := service.NewContainer()
+	c.RegisterFactory("tenant", newBlockingRunner, service.WithIdleTimeout(20*time.Millisecond))
+
+	ctx := context.Background()
+	require.NoError(t, c.StartAll(ctx))
+
+	require.NoError(t, c.EnsureInstance(ctx, "tenant", "a"))
+	assert.Equal(t, []string{"a"}, c.Instances("tenant"))
+	assert.Eventually(t, func() bool {
+		return len(c.Instances("tenant")) == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Empty(t, c.ServiceNames())
+
+	c.StopAll()
+	c.WaitAllStopped(ctx)
+}
+
+func TestStopInstance_ctxErrKeepsContainerRunning(t *testing.T) {
+	c := service.NewContainer()
+	c.RegisterFactory("w", func(key string) service.Runner {
+		return service.New(key).Run(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).Build()
+	})
+	service.New("api").Run(blockUntilDone).Register(c)
+
+	ctx := context.Background()
+	require.NoError(t, c.StartAll(ctx))
+	require.NoError(t, c.EnsureInstance(ctx, "w", "a"))
+	require.NoError(t, c.StopInstance("w", "a"))
+
+	assert.True(t, c.IsServiceRunning("api"))
+	assert.Equal(t, service.ContainerRunning, c.State())
+	assert.NoError(t, c.ShutdownCause())
+	c.StopAll()
+	c.WaitAllStopped(ctx)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// flusher registered with RegisterFlusher
+type flusher struct {
+	name string
+	f    func(ctx context.Context) error
+}
+
+// WithFlushTimeout sets the time budget for all flushers registered with RegisterFlusher, default is 5 seconds.
+// The budget is independent of the time it took the services to stop.
+func WithFlushTimeout(d time.Duration) Option {
+	return func(c *Container) {
+		c.flushTimeout = d
+	}
+}
+
+// RegisterFlusher registers a function that flushes buffered telemetry, e.g. the Shutdown or ForceFlush
+// of trace, metric and log providers. Flushers run sequentially in order of registration
+// after all services stopped, so telemetry emitted during shutdown is not lost.
+// They are executed once per run by WaitAllStopped, before it returns.
+func (c *Container) RegisterFlusher(name string, f func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushers = append(c.flushers, flusher{name: name, f: f})
+}
+
+// flush executes all flushers once per run
+func (c *Container) flush() {
+	c.mu.Lock()
+	if c.flushed {
+		c.mu.Unlock()
+		return
+	}
+	c.flushed = true
+	flushers := append([]flusher{}, c.flushers...)
+	c.mu.Unlock()
+	if len(flushers) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.runCtx), c.flushTimeout)
+	defer cancel()
+	for _, fl := range flushers {
+		start := time.Now()
+		var err error
+		c.callSafe("flusher", func() {
+			err = fl.f(ctx)
+		}, "flusher", fl.name)
+		if err != nil {
+			c.log.Error("Failed to flush", "flusher", fl.name, "error", err, "duration", time.Since(start), "container", c.name)
+		} else {
+			c.log.Debug("Flushed", "flusher", fl.name, "duration", time.Since(start), "container", c.name)
+		}
+	}
+}
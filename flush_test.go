@@ -0,0 +1,45 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFlusher(t *testing.T) {
+	c := service.NewContainer(service.WithFlushTimeout(time.Second))
+	serviceStopped := atomic.Bool{}
+	service.New("s1").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		serviceStopped.Store(true)
+		return nil
+	}).Register(c)
+
+	var calls []string
+	c.RegisterFlusher("traces", func(ctx context.Context) error {
+		assert.True(t, serviceStopped.Load(), "flusher must run after services stopped")
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		calls = append(calls, "traces")
+		return errors.New("exporter unavailable")
+	})
+	c.RegisterFlusher("metrics", func(ctx context.Context) error {
+		calls = append(calls, "metrics")
+		return nil
+	})
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	c.WaitAllStopped(context.Background())
+
+	assert.Equal(t, []string{"traces", "metrics"}, calls)
+}
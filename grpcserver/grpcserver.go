@@ -0,0 +1,106 @@
+// Package grpcserver provides a service that serves a grpc.Server:
+//
+//	c := service.NewContainer()
+//	c.Register(grpcserver.New("api", srv, lis))
+//
+// It is a separate module, so the core package does not depend on gRPC.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/niondir/go-service"
+	"google.golang.org/grpc"
+)
+
+var _ service.Runner = &Server{}
+
+// Server is a service that serves a grpc.Server on a listener
+type Server struct {
+	name        string
+	srv         *grpc.Server
+	lis         net.Listener
+	gracePeriod time.Duration
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+type Option func(s *Server)
+
+// WithGracePeriod limits the time to wait for open RPCs on shutdown, default is 5 seconds.
+// Afterward the server is stopped and the remaining RPCs are canceled.
+func WithGracePeriod(d time.Duration) Option {
+	return func(s *Server) {
+		s.gracePeriod = d
+	}
+}
+
+// New creates a service that serves srv on lis. When the context of Run is canceled, the server is stopped
+// gracefully with GracefulStop and forcefully with Stop after the grace period, see WithGracePeriod.
+// A grpc.Server cannot be started again after it was stopped, Run returns an error when it is restarted.
+func New(name string, srv *grpc.Server, lis net.Listener, opts ...Option) *Server {
+	s := &Server{
+		name:        name,
+		srv:         srv,
+		lis:         lis,
+		gracePeriod: 5 * time.Second,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+func (s *Server) String() string {
+	return s.name
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	s.mu.Lock()
+	stopped := s.stopped
+	s.stopped = true
+	s.mu.Unlock()
+	if stopped {
+		return fmt.Errorf("grpc server '%s' cannot be started again after stop", s.name)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.srv.Serve(s.lis)
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, grpc.ErrServerStopped) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	graceful := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(graceful)
+	}()
+	t := time.NewTimer(s.gracePeriod)
+	defer t.Stop()
+	var err error
+	select {
+	case <-graceful:
+	case <-t.C:
+		err = fmt.Errorf("grpc server '%s' did not stop gracefully within %s", s.name, s.gracePeriod)
+		s.srv.Stop()
+		<-graceful
+	}
+	if serr := <-serveErr; serr != nil && !errors.Is(serr, grpc.ErrServerStopped) {
+		err = errors.Join(err, serr)
+	}
+	return err
+}
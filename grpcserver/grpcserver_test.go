@@ -0,0 +1,77 @@
+package grpcserver_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/niondir/go-service/grpcserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestServer(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+
+	c := service.NewContainer()
+	c.Register(grpcserver.New("api", srv, lis))
+	require.NoError(t, c.StartAll(context.Background()))
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+
+	require.NoError(t, c.StopAllAndWait(ctx))
+	assert.Equal(t, service.ContainerStopped, c.State())
+}
+
+type blockingHealth struct {
+	healthpb.UnimplementedHealthServer
+	started chan struct{}
+}
+
+func (h *blockingHealth) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	close(h.started)
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+func TestServer_gracePeriod(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	h := &blockingHealth{started: make(chan struct{})}
+	healthpb.RegisterHealthServer(srv, h)
+
+	c := service.NewContainer()
+	c.Register(grpcserver.New("api", srv, lis, grpcserver.WithGracePeriod(20*time.Millisecond)))
+	require.NoError(t, c.StartAll(context.Background()))
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	stream, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	go func() {
+		_, _ = stream.Recv()
+	}()
+	<-h.started
+
+	err = c.StopAllAndWait(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did not stop gracefully")
+}
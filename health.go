@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HealthReport is the aggregated health of all services in a container, see Container.Health
+type HealthReport struct {
+	Container string
+	// Healthy is true when all services are healthy
+	Healthy  bool
+	Services []ServiceHealth
+}
+
+// ServiceHealth is the result of the health check of a single service
+type ServiceHealth struct {
+	Name  string
+	State ServiceState
+	// Err is nil when the service is healthy
+	Err error
+	// Duration of the health check
+	Duration time.Duration
+}
+
+// Err joins the errors of all unhealthy services, nil when all services are healthy
+func (r HealthReport) Err() error {
+	var errs []error
+	for _, s := range r.Services {
+		if s.Err != nil {
+			errs = append(errs, fmt.Errorf("service '%s' unhealthy: %w", s.Name, s.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Health checks the health of all registered services concurrently.
+// Running services are healthy unless they implement HealthChecker and report an error,
+// panics of health checks are reported as *PanicError.
+// Services that are not running are unhealthy, non-critical services are reported but do not
+// affect the overall health, see CriticalityReporter.
+// Services not started in the current run are not reported, see WithProfiles and WithDisabledServices.
+func (c *Container) Health(ctx context.Context) HealthReport {
+	c.mu.Lock()
+	services := slices.DeleteFunc(c.orderedServices(), func(s *serviceInfo) bool {
+		return c.excluded[s.name]
+	})
+	states := make([]ServiceState, len(services))
+	for i, s := range services {
+		states[i] = StateRegistered
+		if rc, ok := c.runContexts[s.name]; ok {
+			states[i] = rc.state
+		}
+	}
+	c.mu.Unlock()
+
+	report := HealthReport{
+		Container: c.name,
+		Healthy:   true,
+		Services:  make([]ServiceHealth, len(services)),
+	}
+	wg := sync.WaitGroup{}
+	for i, s := range services {
+		report.Services[i] = ServiceHealth{Name: s.name, State: states[i]}
+		if states[i] != StateRunning {
+			report.Services[i].Err = fmt.Errorf("service is %s", states[i])
+			continue
+		}
+		checker, ok := s.service.(HealthChecker)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			report.Services[i].Err = checkHealth(ctx, checker)
+			report.Services[i].Duration = time.Since(start)
+		}()
+	}
+	wg.Wait()
+
+	for i, s := range services {
+		if report.Services[i].Err != nil && s.critical {
+			report.Healthy = false
+		}
+	}
+	return report
+}
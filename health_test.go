@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthService reports the configured health
+type healthService struct {
+	name   string
+	health error
+}
+
+func (s *healthService) String() string {
+	return s.name
+}
+
+func (s *healthService) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (s *healthService) Health(ctx context.Context) error {
+	return s.health
+}
+
+func TestHealth(t *testing.T) {
+	c := service.NewContainer()
+	db := &healthService{name: "db"}
+	c.Register(db)
+	service.New("plain").Run(blockUntilDone).Register(c)
+
+	report := c.Health(context.Background())
+	assert.False(t, report.Healthy, "services are not running yet")
+
+	require.NoError(t, c.StartAll(context.Background()))
+	report = c.Health(context.Background())
+	assert.True(t, report.Healthy)
+	assert.NoError(t, report.Err())
+
+	unhealthy := errors.New("connection lost")
+	db.health = unhealthy
+	report = c.Health(context.Background())
+	assert.False(t, report.Healthy)
+	assert.ErrorIs(t, report.Err(), unhealthy)
+	assert.Equal(t, "db", report.Services[0].Name)
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestHealth_profiles(t *testing.T) {
+	c := newRoleContainer()
+	require.NoError(t, c.StartAll(context.Background(), service.WithProfiles("worker")))
+	service.New("late-api").Run(blockUntilDone).Tags("api").Register(c)
+
+	report := c.Health(context.Background())
+	assert.True(t, report.Healthy, "services not selected by the profiles are ignored")
+	names := make([]string, 0, len(report.Services))
+	for _, s := range report.Services {
+		names = append(names, s.Name)
+	}
+	assert.ElementsMatch(t, []string{"db", "worker", "scheduler"}, names)
+	assert.Equal(t, []string{"cache", "api"}, c.StartReport().Skipped)
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
+
+// panicHealthService panics in Health
+type panicHealthService struct {
+	healthService
+}
+
+func (s *panicHealthService) Health(ctx context.Context) error {
+	panic("health check bug")
+}
+
+func TestHealth_panic(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(&panicHealthService{healthService{name: "buggy"}})
+	require.NoError(t, c.StartAll(context.Background()))
+
+	report := c.Health(context.Background())
+	assert.False(t, report.Healthy)
+	var panicErr *service.PanicError
+	assert.ErrorAs(t, report.Err(), &panicErr)
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestRestartOnUnhealthy(t *testing.T) {
+	c := service.NewContainer(service.WithHealthCheckInterval(5 * time.Millisecond))
+	var runs atomic.Int32
+	var healthy atomic.Bool
+	var cause error
+	causeMu := sync.Mutex{}
+	service.New("worker").Run(func(ctx context.Context) error {
+		runs.Add(1)
+		<-ctx.Done()
+		causeMu.Lock()
+		cause = context.Cause(ctx)
+		causeMu.Unlock()
+		healthy.Store(true)
+		return nil
+	}).Health(func(ctx context.Context) error {
+		if healthy.Load() {
+			return nil
+		}
+		return errors.New("stuck")
+	}).RestartOnUnhealthy(3, service.Backoff{Initial: time.Millisecond}).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	assert.Eventually(t, func() bool {
+		return runs.Load() == 2
+	}, time.Second, 5*time.Millisecond)
+	causeMu.Lock()
+	assert.ErrorIs(t, cause, service.ErrUnhealthy)
+	causeMu.Unlock()
+	assert.Equal(t, 1, c.Status()[0].Restarts)
+	assert.Equal(t, service.ContainerRunning, c.State())
+
+	c.StopAll()
+	assert.NoError(t, c.WaitAllStopped(context.Background()))
+	assert.Equal(t, int32(2), runs.Load())
+}
@@ -0,0 +1,133 @@
+// Package healthserver provides a service that serves the liveness and readiness of a service.Container via HTTP,
+// e.g. for load balancers and Kubernetes probes:
+//
+//	c := service.NewContainer()
+//	c.Register(healthserver.New(c, ":8081"))
+//
+// GET /livez responds 200 as long as the container is not stopping, stopped or failed.
+// GET /readyz responds 200 when the container is running and all critical services are healthy, see service.HealthChecker.
+// Failing probes respond 503 with the reason in the body.
+package healthserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/niondir/go-service"
+)
+
+var _ service.Runner = &Server{}
+
+// Container is the read-only view on the container served by the Server
+type Container interface {
+	service.Introspector
+	service.StateReporter
+}
+
+// Server is a service that serves /livez and /readyz for a container
+type Server struct {
+	container       Container
+	addr            string
+	checkTimeout    time.Duration
+	shutdownTimeout time.Duration
+	mux             *http.ServeMux
+}
+
+type Option func(s *Server)
+
+// WithCheckTimeout limits the time the health checks of the services may take per request, default is 2 seconds
+func WithCheckTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.checkTimeout = d
+	}
+}
+
+// WithShutdownTimeout limits the time to wait for open requests on shutdown, default is 5 seconds
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout = d
+	}
+}
+
+// New creates a health server listening on addr. The server must be registered as service to be started.
+func New(c Container, addr string, opts ...Option) *Server {
+	s := &Server{
+		container:       c,
+		addr:            addr,
+		checkTimeout:    2 * time.Second,
+		shutdownTimeout: 5 * time.Second,
+		mux:             http.NewServeMux(),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	s.mux.HandleFunc("GET /livez", s.livez)
+	s.mux.HandleFunc("GET /readyz", s.readyz)
+	return s
+}
+
+func (s *Server) String() string {
+	return "healthserver"
+}
+
+// Handler returns the handler serving the probes, e.g. to mount it into an existing HTTP server
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	l, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("health server failed to listen on %s: %w", s.addr, err)
+	}
+	srv := &http.Server{
+		Handler:           s.mux,
+		ReadHeaderTimeout: s.checkTimeout,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(l)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.shutdownTimeout)
+	defer cancel()
+	err = srv.Shutdown(shutdownCtx)
+	if serr := <-serveErr; !errors.Is(serr, http.ErrServerClosed) {
+		err = errors.Join(err, serr)
+	}
+	return err
+}
+
+func (s *Server) livez(w http.ResponseWriter, r *http.Request) {
+	state := s.container.State()
+	if state == service.ContainerStopping || state == service.ContainerStopped || state == service.ContainerFailed {
+		http.Error(w, fmt.Sprintf("container '%s' is %s", s.container.Name(), state), http.StatusServiceUnavailable)
+		return
+	}
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	state := s.container.State()
+	if state != service.ContainerRunning {
+		http.Error(w, fmt.Sprintf("container '%s' is %s", s.container.Name(), state), http.StatusServiceUnavailable)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), s.checkTimeout)
+	defer cancel()
+	report := s.container.Health(ctx)
+	if !report.Healthy {
+		http.Error(w, report.Err().Error(), http.StatusServiceUnavailable)
+		return
+	}
+	_, _ = fmt.Fprintln(w, "ok")
+}
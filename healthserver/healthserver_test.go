@@ -0,0 +1,70 @@
+package healthserver_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/niondir/go-service/healthserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unhealthyService reports an error from Health
+type unhealthyService struct{}
+
+func (s *unhealthyService) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (s *unhealthyService) Health(ctx context.Context) error {
+	return errors.New("database unreachable")
+}
+
+func probe(t *testing.T, h http.Handler, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestServer(t *testing.T) {
+	c := service.NewContainer()
+	hs := healthserver.New(c, "127.0.0.1:0")
+	c.Register(hs)
+
+	code, _ := probe(t, hs.Handler(), "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code, "not ready before start")
+
+	require.NoError(t, c.StartAll(context.Background()))
+	code, _ = probe(t, hs.Handler(), "/livez")
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = probe(t, hs.Handler(), "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	code, _ = probe(t, hs.Handler(), "/livez")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
+
+func TestServer_unhealthy(t *testing.T) {
+	c := service.NewContainer()
+	hs := healthserver.New(c, "127.0.0.1:0")
+	c.Register(hs)
+	c.Register(&unhealthyService{})
+
+	require.NoError(t, c.StartAll(context.Background()))
+	code, body := probe(t, hs.Handler(), "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "database unreachable")
+	code, _ = probe(t, hs.Handler(), "/livez")
+	assert.Equal(t, http.StatusOK, code, "unhealthy services do not affect liveness")
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
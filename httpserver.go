@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HTTPServerOption configures FromHTTPServer
+type HTTPServerOption func(s *httpServerService)
+
+// WithGracefulShutdown limits the time Run waits for open requests after its context is canceled,
+// default is 5 seconds. Afterward the remaining connections are closed.
+func WithGracefulShutdown(d time.Duration) HTTPServerOption {
+	return func(s *httpServerService) {
+		s.shutdownTimeout = d
+	}
+}
+
+// FromHTTPServer returns a service that serves srv on srv.Addr like ListenAndServe.
+// When the context of Run is canceled, the server is shut down gracefully, see WithGracefulShutdown.
+// http.ErrServerClosed is not handled as error. The service is ready once the server is listening, see Readier.
+// An http.Server cannot be started again after it was shut down, Run returns an error when it is restarted.
+func FromHTTPServer(name string, srv *http.Server, opts ...HTTPServerOption) Runner {
+	s := &httpServerService{
+		name:            name,
+		srv:             srv,
+		shutdownTimeout: 5 * time.Second,
+		listening:       make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+type httpServerService struct {
+	name            string
+	srv             *http.Server
+	shutdownTimeout time.Duration
+
+	mu        sync.Mutex
+	listening chan struct{}
+	closed    bool
+}
+
+func (s *httpServerService) String() string {
+	return s.name
+}
+
+func (s *httpServerService) Run(ctx context.Context) error {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return fmt.Errorf("http server '%s' cannot be started again after shutdown", s.name)
+	}
+
+	addr := s.srv.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("http server '%s' failed to listen on %s: %w", s.name, addr, err)
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.srv.Serve(l)
+	}()
+	close(s.listening)
+
+	select {
+	case err := <-serveErr:
+		s.markClosed()
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+	s.markClosed()
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.shutdownTimeout)
+	defer cancel()
+	err = s.srv.Shutdown(shutdownCtx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errors.Join(fmt.Errorf("http server '%s' did not shut down within %s: %w", s.name, s.shutdownTimeout, err), s.srv.Close())
+	}
+	if serr := <-serveErr; !errors.Is(serr, http.ErrServerClosed) {
+		err = errors.Join(err, serr)
+	}
+	return err
+}
+
+func (s *httpServerService) markClosed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+// Ready blocks until the server is listening, see Readier
+func (s *httpServerService) Ready(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.listening:
+		return nil
+	}
+}
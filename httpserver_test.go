@@ -0,0 +1,89 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
+
+func TestFromHTTPServer(t *testing.T) {
+	addr := freeAddr(t)
+	srv := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	})}
+	c := service.NewContainer()
+	c.Register(service.FromHTTPServer("api", srv))
+	require.NoError(t, c.StartAll(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, c.WaitAllReady(ctx))
+
+	res, err := http.Get("http://" + addr)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	assert.Equal(t, "hello", string(body))
+
+	require.NoError(t, c.StopAllAndWait(ctx))
+	assert.Equal(t, service.ContainerStopped, c.State())
+	_, err = http.Get("http://" + addr)
+	assert.Error(t, err)
+}
+
+func TestFromHTTPServer_gracefulShutdown(t *testing.T) {
+	addr := freeAddr(t)
+	started := make(chan struct{})
+	srv := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+	})}
+	c := service.NewContainer()
+	c.Register(service.FromHTTPServer("api", srv, service.WithGracefulShutdown(time.Second)))
+	require.NoError(t, c.StartAll(context.Background()))
+	require.NoError(t, c.WaitAllReady(context.Background()))
+
+	result := make(chan string, 1)
+	go func() {
+		res, err := http.Get("http://" + addr)
+		if err != nil {
+			result <- err.Error()
+			return
+		}
+		body, _ := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		result <- string(body)
+	}()
+	<-started
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+	assert.Equal(t, "done", <-result)
+}
+
+func TestFromHTTPServer_listenError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	c := service.NewContainer()
+	c.Register(service.FromHTTPServer("api", &http.Server{Addr: l.Addr().String()}))
+	require.NoError(t, c.StartAll(context.Background()))
+	err = c.WaitAllStopped(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to listen")
+	assert.Equal(t, service.ContainerFailed, c.State())
+}
@@ -21,9 +21,72 @@ type Initer interface {
 	Init(ctx context.Context) error
 }
 
-// TODO: We want to refactor this to accept a context, but we have legacy code to support
+// ReadyWaiter is the legacy, timeout based readiness interface.
+//
+// Deprecated: Implement Readier instead. ReadyWaiter implementations are still supported by the container,
+// they are adapted with FromReadyWaiter.
 type ReadyWaiter interface {
 	// WaitReady blocks until the service is ready or the timeout is reached
 	// It returns true if the service is ready, false if the timeout is reached
 	WaitReady(timeout time.Duration) bool
 }
+
+// ShutdownPrioritizer can be optionally implemented to control the order in which services are stopped
+// Services with a higher priority are stopped first, the container waits for them to return before
+// the next lower priority is stopped. Services with the same priority are stopped concurrently.
+// Services that do not implement the interface have priority 0.
+type ShutdownPrioritizer interface {
+	ShutdownPriority() int
+}
+
+// InitBarrierUser can be optionally implemented to coordinate the initialization with named init barriers.
+// Barriers are declared on the container with WithInitBarrier.
+// The service is initialized before all services that are initialized after the barriers returned by InitBefore
+// and after all services that are initialized before the barriers returned by InitAfter.
+type InitBarrierUser interface {
+	InitBefore() []string
+	InitAfter() []string
+}
+
+// Readier can be optionally implemented by services that need some time after Run was called to become ready,
+// e.g. to open a listener. It supersedes the timeout based ReadyWaiter.
+type Readier interface {
+	// Ready blocks until the service is ready and returns nil.
+	// It returns an error when ctx is done before the service is ready or the service can not become ready.
+	Ready(ctx context.Context) error
+}
+
+// Phaser can be optionally implemented to group the startup in phases.
+// All services of a lower phase are initialized and started before the services of a higher phase.
+// Services that do not implement the interface are in phase 0.
+type Phaser interface {
+	Phase() int
+}
+
+// Tagger can be optionally implemented to attach tags to a service, e.g. "worker" or "http".
+// Tags are reported with the ServiceStatus.
+type Tagger interface {
+	Tags() []string
+}
+
+// Dependent can be optionally implemented to declare the names of services that must be
+// initialized and started before the service. Services are initialized in topological order of their dependencies,
+// Run is called once all dependencies are ready, see Readier.
+// Unknown or cyclic dependencies fail the startup.
+type Dependent interface {
+	DependsOn() []string
+}
+
+// CriticalityReporter can be optionally implemented to mark a service as non-critical.
+// When a non-critical service returns an error from Init or Run, the error is recorded, see Container.Errors,
+// and the other services keep running. Services depending on a failed service fail to start, see Dependent.
+// Services that do not implement the interface are critical.
+type CriticalityReporter interface {
+	Critical() bool
+}
+
+// HealthChecker can be optionally implemented by services to report their health, see Container.Health.
+type HealthChecker interface {
+	// Health returns nil when the service is healthy. It must return quickly and respect ctx.
+	Health(ctx context.Context) error
+}
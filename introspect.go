@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+)
+
+var _ Introspector = &Container{}
+var _ StateReporter = &Container{}
+var _ ServiceInspector = &Container{}
+var _ StatsReporter = &Container{}
+var _ EventSubscriber = &Container{}
+
+// Introspector is the read-only view on a container.
+// Monitoring and status modules should depend on this interface instead of the Container,
+// so they can not change the lifecycle and can be tested with fakes.
+// The interface is not extended, further read-only views are separate interfaces, e.g. StateReporter.
+type Introspector interface {
+	Name() string
+	RunID() string
+	IsRunning() bool
+	RunningCount() int
+	ServiceNames() []string
+	Status() []ServiceStatus
+	WatchStatus(ctx context.Context) <-chan []ServiceStatus
+	ServiceErrors() map[string]error
+	StartReport() *StartReport
+	// Errors is the history of all errors of the current run, see Container.Errors
+	Errors() []ServiceError
+	Health(ctx context.Context) HealthReport
+}
+
+// StateReporter is the read-only view on the lifecycle state of a container, see Container.State
+type StateReporter interface {
+	State() ContainerState
+}
+
+// ServiceInspector is the read-only view on single services of a container
+type ServiceInspector interface {
+	IsServiceRunning(name string) bool
+	ServiceState(name string) (ServiceState, error)
+	Capabilities(name string) ([]Capability, error)
+}
+
+// StatsReporter is the read-only view on the summary of a container, see Container.Stats
+type StatsReporter interface {
+	Stats() Stats
+}
+
+// EventSubscriber is the read-only view on the lifecycle events of a container, see Container.Subscribe
+type EventSubscriber interface {
+	Subscribe(f func(e Event)) (unsubscribe func())
+}
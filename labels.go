@@ -0,0 +1,64 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"regexp"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+)
+
+// pprof label keys set on all goroutines of a service, including goroutines started by the service
+const (
+	labelContainer = "container"
+	labelRun       = "run"
+	labelService   = "service"
+)
+
+// withServiceContext executes f with the context of the service: the logger, the tracer, the error reporter
+// and the declared dependencies of the service are added, see LoggerFromContext, StartSpan, ReportError and Sibling.
+// The context carries pprof labels identifying the service, goroutines started inside f inherit the labels.
+func (c *Container) withServiceContext(ctx context.Context, s *serviceInfo, f func(ctx context.Context)) {
+	ctx = withLogger(ctx, c.serviceLogger(s))
+	ctx = c.withSpanStarter(ctx, s)
+	ctx = c.withErrorReporter(ctx, s)
+	ctx = c.withSiblingResolver(ctx, s)
+	labels := pprof.Labels(labelContainer, c.name, labelRun, c.RunID(), labelService, s.name)
+	pprof.Do(ctx, labels, f)
+}
+
+var labelPairRegex = regexp.MustCompile(`"([^"]*)":"([^"]*)"`)
+
+// labeledGoroutines counts the goroutines labeled with the current run of the container by service name
+func (c *Container) labeledGoroutines() map[string]int {
+	runID := c.RunID()
+	buf := &bytes.Buffer{}
+	_ = pprof.Lookup("goroutine").WriteTo(buf, 1)
+
+	counts := map[string]int{}
+	count := 0
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Each record starts with "<count> @ <addresses>", optionally followed by "# labels: {...}"
+		if n, _, ok := strings.Cut(line, " @ "); ok {
+			count, _ = strconv.Atoi(n)
+			continue
+		}
+		labelsText, ok := strings.CutPrefix(line, "# labels: ")
+		if !ok {
+			continue
+		}
+		labels := map[string]string{}
+		for _, m := range labelPairRegex.FindAllStringSubmatch(labelsText, -1) {
+			labels[m[1]] = m[2]
+		}
+		if labels[labelContainer] != c.name || labels[labelRun] != runID {
+			continue
+		}
+		counts[labels[labelService]] += count
+	}
+	return counts
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"runtime"
+	"time"
+)
+
+// leakCheckGracePeriod is the time goroutines of stopped services get to return before they are reported as leaked
+const leakCheckGracePeriod = 100 * time.Millisecond
+
+// LeakReport lists goroutines that are still running after all services stopped
+type LeakReport struct {
+	// GoroutinesBefore is the number of goroutines before StartAll
+	GoroutinesBefore int
+	// GoroutinesAfter is the number of goroutines after all services stopped
+	GoroutinesAfter int
+	// Leaked is the number of goroutines still labeled with a service, by service name
+	Leaked map[string]int
+}
+
+// WithLeakCheck enables the detection of leaked goroutines.
+// All goroutines started by a service inherit pprof labels of the service. When WaitAllStopped
+// observed all services to stop, goroutines still carrying the labels are logged as leaked and reported by LeakReport.
+func WithLeakCheck() Option {
+	return func(c *Container) {
+		c.leakCheck = true
+	}
+}
+
+// LeakReport returns the result of the leak check of the last run.
+// Returns nil if the leak check is disabled or WaitAllStopped did not observe all services to stop yet.
+func (c *Container) LeakReport() *LeakReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.leakReport
+}
+
+// checkLeaks must be called after all services stopped
+func (c *Container) checkLeaks() {
+	if !c.leakCheck {
+		return
+	}
+	c.mu.Lock()
+	done := c.leakReport != nil
+	c.mu.Unlock()
+	if done {
+		return
+	}
+
+	// Goroutines of services might just be returning, give them some time
+	leaked := c.labeledGoroutines()
+	for deadline := time.Now().Add(leakCheckGracePeriod); len(leaked) > 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		leaked = c.labeledGoroutines()
+	}
+
+	report := &LeakReport{
+		GoroutinesBefore: c.goroutinesBefore,
+		GoroutinesAfter:  runtime.NumGoroutine(),
+		Leaked:           leaked,
+	}
+	for name, count := range leaked {
+		c.log.Warn("Service leaked goroutines", "name", name, "count", count, "container", c.name, "run", c.RunID())
+	}
+	c.mu.Lock()
+	c.leakReport = report
+	c.mu.Unlock()
+}
@@ -0,0 +1,45 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeakCheck(t *testing.T) {
+	c := service.NewContainer(service.WithLeakCheck())
+	release := make(chan struct{})
+	defer close(release)
+
+	service.New("leaky").Run(func(ctx context.Context) error {
+		go func() {
+			<-release
+		}()
+		<-ctx.Done()
+		return nil
+	}).Register(c)
+	service.New("clean").Run(func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			<-ctx.Done()
+		}()
+		<-done
+		return nil
+	}).Register(c)
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, c.LeakReport())
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	report := c.LeakReport()
+	require.NotNil(t, report)
+	assert.Equal(t, map[string]int{"leaky": 1}, report.Leaked)
+	assert.Greater(t, report.GoroutinesBefore, 0)
+}
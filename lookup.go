@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrUndeclaredDependency is wrapped by the error of Sibling when the service did not declare the dependency, see Dependent
+var ErrUndeclaredDependency = errors.New("undeclared dependency")
+
+// Get returns the registered instance of the service with the given name, e.g. to call business methods on it.
+// Returns false if no service with the name is registered.
+func (c *Container) Get(name string) (Runner, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.services {
+		if s.name == name {
+			return s.service, true
+		}
+	}
+	return nil, false
+}
+
+// Get returns the first registered service of type T in order of registration, e.g. Get[*Database](c).
+// Returns false if no service of the type is registered.
+func Get[T Runner](c *Container) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.services {
+		if t, ok := s.service.(T); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+type siblingResolverKey struct{}
+
+// Sibling returns the registered instance of the service with the given name from the context of Init or Run,
+// e.g. Sibling[*Database](ctx, "db"). The calling service must declare the dependency, see Dependent,
+// so cross-service access follows the declared dependencies and the sibling is initialized before.
+// Returns an error wrapping ErrUndeclaredDependency if the dependency is not declared, or an error if ctx
+// does not belong to a service or the sibling is not of type T.
+func Sibling[T any](ctx context.Context, name string) (T, error) {
+	var zero T
+	resolve, ok := ctx.Value(siblingResolverKey{}).(func(name string) (Runner, error))
+	if !ok {
+		return zero, fmt.Errorf("sibling '%s' requested outside of a service", name)
+	}
+	r, err := resolve(name)
+	if err != nil {
+		return zero, err
+	}
+	t, ok := r.(T)
+	if !ok {
+		return zero, fmt.Errorf("sibling '%s' is %T, not %T", name, r, zero)
+	}
+	return t, nil
+}
+
+// withSiblingResolver adds the resolver of the declared dependencies of the service to ctx, see Sibling
+func (c *Container) withSiblingResolver(ctx context.Context, s *serviceInfo) context.Context {
+	return context.WithValue(ctx, siblingResolverKey{}, func(name string) (Runner, error) {
+		if !slices.Contains(s.dependsOn, name) {
+			return nil, fmt.Errorf("service '%s' requested sibling '%s': %w", s.name, name, ErrUndeclaredDependency)
+		}
+		r, ok := c.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("sibling '%s' not registered in container '%s'", name, c.name)
+		}
+		return r, nil
+	})
+}
@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type database struct {
+	dsn string
+}
+
+func (d *database) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (d *database) String() string {
+	return "db"
+}
+
+func TestContainer_Get(t *testing.T) {
+	c := service.NewContainer()
+	db := &database{dsn: "postgres://"}
+	c.Register(db)
+	service.New("api").Run(blockUntilDone).Register(c)
+
+	r, ok := c.Get("db")
+	require.True(t, ok)
+	assert.Same(t, db, r)
+	_, ok = c.Get("unknown")
+	assert.False(t, ok)
+
+	found, ok := service.Get[*database](c)
+	require.True(t, ok)
+	assert.Equal(t, "postgres://", found.dsn)
+	_, ok = service.Get[*capableService](c)
+	assert.False(t, ok)
+}
+
+func TestSibling(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(&database{dsn: "postgres://"})
+	var dsn string
+	var undeclared, wrongType error
+	service.New("api").DependsOn("db").Init(func(ctx context.Context) error {
+		db, err := service.Sibling[*database](ctx, "db")
+		if err != nil {
+			return err
+		}
+		dsn = db.dsn
+		_, wrongType = service.Sibling[*capableService](ctx, "db")
+		return nil
+	}).Run(blockUntilDone).Register(c)
+	service.New("worker").Init(func(ctx context.Context) error {
+		_, undeclared = service.Sibling[*database](ctx, "db")
+		return nil
+	}).Run(blockUntilDone).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+
+	assert.Equal(t, "postgres://", dsn)
+	assert.ErrorIs(t, undeclared, service.ErrUndeclaredDependency)
+	require.Error(t, wrongType)
+	assert.Contains(t, wrongType.Error(), "not *service_test.capableService")
+
+	_, err := service.Sibling[*database](context.Background(), "db")
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LoopOption configures Loop and Forever
+type LoopOption func(l *loopConfig)
+
+type loopConfig struct {
+	backoff Backoff
+}
+
+// WithErrorBackoff sets the delay after failed iterations, default is Backoff{} with its defaults.
+// Backoff.MaxRestarts limits the consecutive failed iterations, afterward the RunFunc returns the last error.
+func WithErrorBackoff(b Backoff) LoopOption {
+	return func(l *loopConfig) {
+		l.backoff = b
+	}
+}
+
+// Loop returns a RunFunc that calls body every interval until ctx is done.
+// The first call is made right away. See Forever for error and panic handling.
+func Loop(interval time.Duration, body func(ctx context.Context) error, opts ...LoopOption) RunFunc {
+	return loop(interval, body, opts)
+}
+
+// Forever returns a RunFunc that calls body again as soon as it returned until ctx is done,
+// e.g. for a body that blocks while consuming a queue.
+// Panics inside body are recovered and handled as error of the iteration, see PanicError.
+// Failed iterations are logged and retried after a delay according to the error backoff,
+// the backoff is reset by the next successful iteration. See WithErrorBackoff.
+func Forever(body func(ctx context.Context) error, opts ...LoopOption) RunFunc {
+	return loop(0, body, opts)
+}
+
+func loop(interval time.Duration, body func(ctx context.Context) error, opts []LoopOption) RunFunc {
+	cfg := &loopConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	backoff := cfg.backoff.withDefaults()
+
+	return func(ctx context.Context) error {
+		logger := LoggerFromContext(ctx)
+		failures := 0
+		for ctx.Err() == nil {
+			delay := interval
+			if err := callIteration(ctx, body); err != nil && ctx.Err() == nil {
+				if backoff.MaxRestarts > 0 && failures >= backoff.MaxRestarts {
+					return fmt.Errorf("loop failed %d times in a row: %w", failures+1, err)
+				}
+				delay = max(interval, backoff.delay(failures))
+				failures++
+				logger.Warn("Loop iteration failed", "error", err, "failures", failures, "delay", delay)
+			} else {
+				failures = 0
+			}
+
+			if delay <= 0 {
+				continue
+			}
+			t := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+			case <-t.C:
+			}
+		}
+		return nil
+	}
+}
+
+// callIteration calls body and returns recovered panics as *PanicError
+func callIteration(ctx context.Context, body func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r)
+		}
+	}()
+	return body(ctx)
+}
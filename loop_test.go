@@ -0,0 +1,48 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoop(t *testing.T) {
+	var calls atomic.Int32
+	run := service.Loop(5*time.Millisecond, func(ctx context.Context) error {
+		switch calls.Add(1) {
+		case 2:
+			panic("bug in iteration")
+		case 3:
+			return errors.New("temporary")
+		}
+		return nil
+	}, service.WithErrorBackoff(service.Backoff{Initial: time.Millisecond}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- run(ctx)
+	}()
+	require.Eventually(t, func() bool { return calls.Load() > 4 }, time.Second, time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestForever_maxFailures(t *testing.T) {
+	loopErr := errors.New("broken")
+	var calls atomic.Int32
+	run := service.Forever(func(ctx context.Context) error {
+		calls.Add(1)
+		return loopErr
+	}, service.WithErrorBackoff(service.Backoff{Initial: time.Millisecond, MaxRestarts: 2}))
+
+	err := run(context.Background())
+	assert.ErrorIs(t, err, loopErr)
+	assert.Equal(t, int32(3), calls.Load())
+}
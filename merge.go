@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+)
+
+// defaultNameSeparator separates the name of a child container from the names of its services, see Merge
+const defaultNameSeparator = "/"
+
+// WithNameSeparator sets the separator between the name of a merged or nested container and
+// its service names, default is "/". E.g. service "db" of container "storage" becomes "storage/db".
+func WithNameSeparator(sep string) Option {
+	return func(c *Container) {
+		c.nameSeparator = sep
+	}
+}
+
+// Merge moves all registered services, shutdown callbacks, flushers and resources of the child container into c,
+// see ManageCloser. Service and resource names are prefixed with the name of the child container to avoid collisions, see WithNameSeparator.
+// Dependencies between the services of the child are preserved. Both containers must not be started,
+// the child must not be used afterward.
+func (c *Container) Merge(child *Container) error {
+	if c.IsRunning() || child.IsRunning() {
+		return fmt.Errorf("can not merge container '%s' into '%s' after start", child.name, c.name)
+	}
+
+	child.mu.Lock()
+	services := make([]*serviceInfo, 0, len(child.services))
+	siblings := map[string]bool{}
+	for _, s := range child.services {
+		siblings[s.name] = true
+	}
+	for _, s := range child.services {
+		merged := *s
+		merged.name = c.childServiceName(child.name, s.name)
+		merged.dependsOn = make([]string, len(s.dependsOn))
+		for i, d := range s.dependsOn {
+			if siblings[d] {
+				d = c.childServiceName(child.name, d)
+			}
+			merged.dependsOn[i] = d
+		}
+		services = append(services, &merged)
+	}
+	callbacks := append([]shutdownCallback{}, child.shutdownCallbacks...)
+	flushers := append([]flusher{}, child.flushers...)
+	closers := make([]managedCloser, 0, len(child.closers))
+	for _, mc := range child.closers {
+		closers = append(closers, managedCloser{name: c.childServiceName(child.name, mc.name), closer: mc.closer})
+	}
+	child.mu.Unlock()
+
+	c.mu.Lock()
+	for _, s := range services {
+		for _, existing := range c.services {
+			if existing.name == s.name {
+				c.mu.Unlock()
+				return fmt.Errorf("can not merge container '%s' into '%s': service '%s' already registered", child.name, c.name, s.name)
+			}
+		}
+	}
+	c.shutdownCallbacks = append(c.shutdownCallbacks, callbacks...)
+	c.flushers = append(c.flushers, flushers...)
+	c.closers = append(c.closers, closers...)
+	c.mu.Unlock()
+
+	for _, s := range services {
+		if err := c.addService(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// childServiceName returns the name of a service of a merged or nested container
+func (c *Container) childServiceName(container, name string) string {
+	if container == "" {
+		return name
+	}
+	return container + c.nameSeparator + name
+}
@@ -0,0 +1,52 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	storage := service.NewContainer(service.WithName("storage"))
+	var order []string
+	service.New("api").Init(initRecorder(&order, "storage.api")).DependsOn("db").Run(blockUntilDone).Register(storage)
+	service.New("db").Init(initRecorder(&order, "storage.db")).Run(blockUntilDone).Register(storage)
+
+	c := service.NewContainer(service.WithName("app"), service.WithNameSeparator("."))
+	service.New("db").Init(initRecorder(&order, "db")).Run(blockUntilDone).Register(c)
+	require.NoError(t, c.Merge(storage))
+
+	assert.Equal(t, []string{"db", "storage.api", "storage.db"}, statusNames(c.Status()))
+
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Equal(t, []string{"db", "storage.db", "storage.api"}, order)
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestMerge_closers(t *testing.T) {
+	r := &closeRecorder{}
+	storage := service.NewContainer(service.WithName("storage"))
+	storage.ManageCloser("pool", r.closer("storage.pool", nil))
+	service.New("db").Run(blockUntilDone).Register(storage)
+
+	c := service.NewContainer(service.WithName("app"))
+	c.ManageCloser("file", r.closer("file", nil))
+	require.NoError(t, c.Merge(storage))
+
+	require.NoError(t, c.StartAll(context.Background()))
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+	assert.Equal(t, []string{"storage.pool", "file"}, r.closed)
+}
+
+func TestMerge_collision(t *testing.T) {
+	child := service.NewContainer()
+	service.New("db").Register(child)
+	c := service.NewContainer()
+	service.New("db").Register(c)
+
+	assert.Error(t, c.Merge(child))
+}
@@ -0,0 +1,138 @@
+// Package metrics provides a prometheus.Collector reporting the lifecycle of the services in a service.Container:
+//
+//	c := service.NewContainer()
+//	prometheus.MustRegister(metrics.NewCollector(c))
+//
+// It is a separate module, so the core package does not depend on the Prometheus client.
+package metrics
+
+import (
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = &Collector{}
+
+// states are all service states reported by the state metric
+var states = []service.ServiceState{
+	service.StateRegistered,
+	service.StateInitializing,
+	service.StateRunning,
+	service.StateStopped,
+	service.StateFailed,
+	service.StateAbandoned,
+	service.StateStopping,
+	service.StateWarming,
+}
+
+// Collector reports the state, uptime, restarts, durations and resource usage of all services in a container
+// on every scrape. All metrics are labeled with the container, the run ID and the service.
+type Collector struct {
+	container service.Introspector
+
+	state        *prometheus.Desc
+	uptime       *prometheus.Desc
+	restarts     *prometheus.Desc
+	initDuration *prometheus.Desc
+	runDuration  *prometheus.Desc
+	stopDuration *prometheus.Desc
+	goroutines   *prometheus.Desc
+	cpu          *prometheus.Desc
+}
+
+type Option func(c *collectorConfig)
+
+type collectorConfig struct {
+	namespace   string
+	constLabels prometheus.Labels
+}
+
+// WithNamespace sets the namespace of all metrics, default is "go_service"
+func WithNamespace(ns string) Option {
+	return func(c *collectorConfig) {
+		c.namespace = ns
+	}
+}
+
+// WithConstLabels adds labels to all metrics, e.g. to distinguish multiple applications
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *collectorConfig) {
+		c.constLabels = labels
+	}
+}
+
+// NewCollector creates a collector for the given container. It must be registered at a prometheus.Registerer.
+func NewCollector(c service.Introspector, opts ...Option) *Collector {
+	cfg := &collectorConfig{namespace: "go_service"}
+	for _, o := range opts {
+		o(cfg)
+	}
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(cfg.namespace, "", name), help,
+			append([]string{"container", "run", "service"}, labels...), cfg.constLabels)
+	}
+	return &Collector{
+		container:    c,
+		state:        desc("state", "Current lifecycle state of the service, 1 for the active state.", "state"),
+		uptime:       desc("uptime_seconds", "Time since the service was started, 0 if it is not running."),
+		restarts:     desc("restarts_total", "Number of restarts of the service due to its restart policy."),
+		initDuration: desc("init_duration_seconds", "Time the Init of the service took."),
+		runDuration:  desc("run_duration_seconds", "Time the service was running until Run returned."),
+		stopDuration: desc("stop_duration_seconds", "Time from canceling the service until Run returned."),
+		goroutines:   desc("goroutines", "Goroutines labeled with the service, only reported with service.WithResourceStats."),
+		cpu:          desc("cpu_seconds", "CPU time used by the service during the last Container.SampleCPU call."),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.state
+	ch <- c.uptime
+	ch <- c.restarts
+	ch <- c.initDuration
+	ch <- c.runDuration
+	ch <- c.stopDuration
+	ch <- c.goroutines
+	ch <- c.cpu
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	name := c.container.Name()
+	// The run ID distinguishes the metrics of different runs of the same container, see service.RunInfo
+	runID := c.container.RunID()
+	for _, s := range c.container.Status() {
+		if s.Attached {
+			// Attached tasks might share the name of a service
+			continue
+		}
+		for _, state := range states {
+			v := 0.0
+			if s.State == state {
+				v = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.state, prometheus.GaugeValue, v, name, runID, s.Name, state.String())
+		}
+
+		uptime := 0.0
+		if (s.State == service.StateRunning || s.State == service.StateWarming || s.State == service.StateStopping) && !s.StartedAt.IsZero() {
+			uptime = time.Since(s.StartedAt).Seconds()
+		}
+		ch <- prometheus.MustNewConstMetric(c.uptime, prometheus.GaugeValue, uptime, name, runID, s.Name)
+		ch <- prometheus.MustNewConstMetric(c.restarts, prometheus.CounterValue, float64(s.Restarts), name, runID, s.Name)
+		ch <- prometheus.MustNewConstMetric(c.initDuration, prometheus.GaugeValue, s.InitDuration.Seconds(), name, runID, s.Name)
+		if !s.StartedAt.IsZero() && !s.StoppedAt.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.runDuration, prometheus.GaugeValue, s.StoppedAt.Sub(s.StartedAt).Seconds(), name, runID, s.Name)
+		}
+		if s.StopDuration > 0 {
+			ch <- prometheus.MustNewConstMetric(c.stopDuration, prometheus.GaugeValue, s.StopDuration.Seconds(), name, runID, s.Name)
+		}
+		// Resource usage is only sampled on demand, zero values are not reported
+		if s.Goroutines > 0 {
+			ch <- prometheus.MustNewConstMetric(c.goroutines, prometheus.GaugeValue, float64(s.Goroutines), name, runID, s.Name)
+		}
+		if s.CPU > 0 {
+			ch <- prometheus.MustNewConstMetric(c.cpu, prometheus.GaugeValue, s.CPU.Seconds(), name, runID, s.Name)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package metrics_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/niondir/go-service/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	c := service.NewContainer(service.WithName("app"))
+	service.New("worker").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}).Register(c)
+	collector := metrics.NewCollector(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+
+	expected := `
+# HELP go_service_state Current lifecycle state of the service, 1 for the active state.
+# TYPE go_service_state gauge
+go_service_state{container="app",run="RUN",service="worker",state="Abandoned"} 0
+go_service_state{container="app",run="RUN",service="worker",state="Failed"} 0
+go_service_state{container="app",run="RUN",service="worker",state="Initializing"} 0
+go_service_state{container="app",run="RUN",service="worker",state="Registered"} 0
+go_service_state{container="app",run="RUN",service="worker",state="Running"} 1
+go_service_state{container="app",run="RUN",service="worker",state="Stopped"} 0
+go_service_state{container="app",run="RUN",service="worker",state="Stopping"} 0
+go_service_state{container="app",run="RUN",service="worker",state="Warming"} 0
+# HELP go_service_restarts_total Number of restarts of the service due to its restart policy.
+# TYPE go_service_restarts_total counter
+go_service_restarts_total{container="app",run="RUN",service="worker"} 0
+`
+	expected = strings.ReplaceAll(expected, "RUN", c.RunID())
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "go_service_state", "go_service_restarts_total"))
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "go_service_stop_duration_seconds"))
+}
+
+func TestCollector_resources(t *testing.T) {
+	c := service.NewContainer(service.WithName("app"), service.WithResourceStats())
+	service.New("worker").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}).Register(c)
+	collector := metrics.NewCollector(c)
+	assert.Equal(t, 0, testutil.CollectAndCount(collector, "go_service_goroutines"))
+
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "go_service_goroutines"))
+	assert.Equal(t, 0, testutil.CollectAndCount(collector, "go_service_cpu_seconds"), "not sampled yet")
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
@@ -0,0 +1,220 @@
+// Package metricsserver provides a service that serves the Prometheus metrics and the status of a service.Container via HTTP:
+//
+//	c := service.NewContainer()
+//	c.Register(metricsserver.New(c, ":9090"))
+//
+// GET /metrics serves the registry including the metrics.Collector of the container.
+// GET /status responds the status of all services as JSON.
+// GET /events streams the lifecycle events of the services as server-sent events.
+package metricsserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/niondir/go-service/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var _ service.Runner = &Server{}
+
+// Container is the read-only view on the container served by the Server
+type Container interface {
+	service.Introspector
+	service.StateReporter
+	service.EventSubscriber
+}
+
+// Server is a service that serves /metrics, /status and /events for a container
+type Server struct {
+	container       Container
+	addr            string
+	registry        *prometheus.Registry
+	collectorOpts   []metrics.Option
+	shutdownTimeout time.Duration
+	mux             *http.ServeMux
+}
+
+type Option func(s *Server)
+
+// WithRegistry serves the given registry instead of a new registry with the Go and process collectors.
+// The collector of the container is registered at the registry by New.
+func WithRegistry(r *prometheus.Registry) Option {
+	return func(s *Server) {
+		s.registry = r
+	}
+}
+
+// WithCollectorOptions configures the collector of the container, see metrics.NewCollector
+func WithCollectorOptions(opts ...metrics.Option) Option {
+	return func(s *Server) {
+		s.collectorOpts = append(s.collectorOpts, opts...)
+	}
+}
+
+// WithShutdownTimeout limits the time to wait for open requests on shutdown, default is 5 seconds
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout = d
+	}
+}
+
+// New creates a metrics server listening on addr. The server must be registered as service to be started.
+// Panics when the collector can not be registered at the registry, see prometheus.Registerer.MustRegister.
+func New(c Container, addr string, opts ...Option) *Server {
+	s := &Server{
+		container:       c,
+		addr:            addr,
+		shutdownTimeout: 5 * time.Second,
+		mux:             http.NewServeMux(),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	if s.registry == nil {
+		s.registry = prometheus.NewRegistry()
+		s.registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	}
+	s.registry.MustRegister(metrics.NewCollector(c, s.collectorOpts...))
+	s.mux.Handle("GET /metrics", promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{}))
+	s.mux.HandleFunc("GET /status", s.status)
+	s.mux.HandleFunc("GET /events", s.events)
+	return s
+}
+
+func (s *Server) String() string {
+	return "metricsserver"
+}
+
+// Handler returns the handler serving the metrics, status and events, e.g. to mount it into an existing HTTP server
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// shutdownKey is the context key of the channel that is closed when the http.Server shuts down
+type shutdownKey struct{}
+
+func (s *Server) Run(ctx context.Context) error {
+	shutdown := make(chan struct{})
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		// Event streams do not end on their own and would delay the graceful shutdown
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), shutdownKey{}, (<-chan struct{})(shutdown))
+		},
+	}
+	srv.RegisterOnShutdown(func() { close(shutdown) })
+	// A new http.Server is used for every run, so the service can be restarted
+	return service.FromHTTPServer(s.String(), srv, service.WithGracefulShutdown(s.shutdownTimeout)).Run(ctx)
+}
+
+// serviceStatus is the JSON representation of a service.ServiceStatus
+type serviceStatus struct {
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	Tags      []string   `json:"tags,omitempty"`
+	Restarts  int        `json:"restarts"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	ErrorCode string     `json:"errorCode,omitempty"`
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request) {
+	status := s.container.Status()
+	res := struct {
+		Container string          `json:"container"`
+		State     string          `json:"state"`
+		Services  []serviceStatus `json:"services"`
+	}{
+		Container: s.container.Name(),
+		State:     s.container.State().String(),
+		Services:  make([]serviceStatus, 0, len(status)),
+	}
+	for _, st := range status {
+		ss := serviceStatus{
+			Name:     st.Name,
+			State:    st.State.String(),
+			Tags:     st.Tags,
+			Restarts: st.Restarts,
+		}
+		if !st.StartedAt.IsZero() {
+			ss.StartedAt = &st.StartedAt
+		}
+		if st.Err != nil {
+			ss.Error = st.Err.Error()
+			ss.ErrorCode = string(st.ErrCode)
+		}
+		res.Services = append(res.Services, ss)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// event is the JSON representation of a service.Event
+type event struct {
+	Type      string    `json:"type"`
+	Container string    `json:"container"`
+	Service   string    `json:"service"`
+	Time      time.Time `json:"time"`
+	Error     string    `json:"error,omitempty"`
+	ErrorCode string    `json:"errorCode,omitempty"`
+}
+
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	ctx := r.Context()
+	shutdown, _ := ctx.Value(shutdownKey{}).(<-chan struct{})
+	events := make(chan service.Event)
+	unsubscribe := s.container.Subscribe(func(e service.Event) {
+		select {
+		case events <- e:
+		case <-ctx.Done():
+		}
+	})
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-shutdown:
+			return
+		case e := <-events:
+			ev := event{
+				Type:      e.Type.String(),
+				Container: e.Container,
+				Service:   e.Service,
+				Time:      e.Time,
+				ErrorCode: string(e.Code),
+			}
+			if e.Err != nil {
+				ev.Error = e.Err.Error()
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
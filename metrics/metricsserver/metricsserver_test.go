@@ -0,0 +1,110 @@
+package metricsserver_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/niondir/go-service/metrics/metricsserver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, h http.Handler, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestServer(t *testing.T) {
+	c := service.NewContainer(service.WithName("app"))
+	ms := metricsserver.New(c, "127.0.0.1:0")
+	c.Register(ms)
+	service.New("worker").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	defer func() {
+		require.NoError(t, c.StopAllAndWait(context.Background()))
+	}()
+
+	code, body := get(t, ms.Handler(), "/metrics")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, fmt.Sprintf(`go_service_state{container="app",run="%s",service="worker",state="Running"} 1`, c.RunID()))
+	assert.Contains(t, body, "go_goroutines")
+
+	code, body = get(t, ms.Handler(), "/status")
+	assert.Equal(t, http.StatusOK, code)
+	var status struct {
+		Container string
+		State     string
+		Services  []struct {
+			Name  string
+			State string
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &status))
+	assert.Equal(t, "app", status.Container)
+	assert.Equal(t, "Running", status.State)
+	require.Len(t, status.Services, 2)
+	assert.Equal(t, "worker", status.Services[1].Name)
+	assert.Equal(t, "Running", status.Services[1].State)
+}
+
+func TestServer_withRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := service.NewContainer(service.WithName("app"))
+	ms := metricsserver.New(c, "127.0.0.1:0", metricsserver.WithRegistry(reg))
+	c.Register(ms)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	require.NotEmpty(t, families)
+	_, body := get(t, ms.Handler(), "/metrics")
+	assert.NotContains(t, body, "go_goroutines")
+	assert.Contains(t, body, `go_service_state{container="app",run="",service="metricsserver",state="Registered"} 1`)
+}
+
+func TestServer_events(t *testing.T) {
+	c := service.NewContainer(service.WithName("app"))
+	ms := metricsserver.New(c, "127.0.0.1:0")
+	srv := httptest.NewServer(ms.Handler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events", nil)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	service.New("worker").Register(c)
+
+	scanner := bufio.NewScanner(res.Body)
+	require.True(t, scanner.Scan())
+	assert.Equal(t, "event: Registered", scanner.Text())
+	require.True(t, scanner.Scan())
+	data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+	require.True(t, ok)
+	var e struct {
+		Type      string
+		Container string
+		Service   string
+	}
+	require.NoError(t, json.Unmarshal([]byte(data), &e))
+	assert.Equal(t, "Registered", e.Type)
+	assert.Equal(t, "app", e.Container)
+	assert.Equal(t, "worker", e.Service)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// RunMiddleware wraps the Run method of every service, see Container.Use
+type RunMiddleware func(next RunFunc) RunFunc
+
+// InitMiddleware wraps the Init method of every service, see Container.UseInit
+type InitMiddleware func(next InitFunc) InitFunc
+
+// Use adds middlewares that wrap Run of every service, e.g. for logging, metrics or tracing.
+// The first middleware is the outermost. The chain is applied whenever Run is called,
+// the name of the service is available with ServiceNameFromContext.
+func (c *Container) Use(mw ...RunMiddleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.runMiddlewares = append(c.runMiddlewares, mw...)
+}
+
+// UseInit adds middlewares that wrap Init of every service implementing Initer, see Use
+func (c *Container) UseInit(mw ...InitMiddleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.initMiddlewares = append(c.initMiddlewares, mw...)
+}
+
+// ServiceNameFromContext returns the name of the service, it is available in the context of all Init and Run calls
+func ServiceNameFromContext(ctx context.Context) (name string, ok bool) {
+	return pprof.Label(ctx, labelService)
+}
+
+// chainRun applies the Run middlewares to run
+func (c *Container) chainRun(run RunFunc) RunFunc {
+	c.mu.Lock()
+	mws := c.runMiddlewares
+	c.mu.Unlock()
+	for i := len(mws) - 1; i >= 0; i-- {
+		run = mws[i](run)
+	}
+	return run
+}
+
+// chainInit applies the Init middlewares to init
+func (c *Container) chainInit(init InitFunc) InitFunc {
+	c.mu.Lock()
+	mws := c.initMiddlewares
+	c.mu.Unlock()
+	for i := len(mws) - 1; i >= 0; i-- {
+		init = mws[i](init)
+	}
+	return init
+}
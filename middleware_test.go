@@ -0,0 +1,48 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUse(t *testing.T) {
+	c := service.NewContainer()
+	mu := sync.Mutex{}
+	var calls []string
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+	}
+	wrap := func(prefix string) service.RunMiddleware {
+		return func(next service.RunFunc) service.RunFunc {
+			return func(ctx context.Context) error {
+				name, _ := service.ServiceNameFromContext(ctx)
+				record(prefix + " run " + name)
+				return next(ctx)
+			}
+		}
+	}
+	c.Use(wrap("outer"), wrap("inner"))
+	c.UseInit(func(next service.InitFunc) service.InitFunc {
+		return func(ctx context.Context) error {
+			name, _ := service.ServiceNameFromContext(ctx)
+			record("init " + name)
+			return next(ctx)
+		}
+	})
+	service.New("worker").Run(func(ctx context.Context) error {
+		record("worker")
+		<-ctx.Done()
+		return nil
+	}).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+	assert.Equal(t, []string{"init worker", "outer run worker", "inner run worker", "worker"}, calls)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// StopAll calls StopAll on all containers, e.g. for applications with multiple containers.
+// Containers that were never started are skipped.
+func StopAll(containers ...*Container) {
+	for _, c := range containers {
+		if !c.IsRunning() {
+			continue
+		}
+		c.StopAll()
+	}
+}
+
+// WaitAll waits concurrently until all services of all containers stopped or ctx is done, see WaitAllStopped.
+// Containers that were never started are skipped. Returns the joined errors of all containers.
+func WaitAll(ctx context.Context, containers ...*Container) error {
+	errs := make([]error, len(containers))
+	wg := sync.WaitGroup{}
+	for i, c := range containers {
+		if !c.IsRunning() {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.WaitAllStopped(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
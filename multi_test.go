@@ -0,0 +1,45 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitAll(t *testing.T) {
+	api := service.NewContainer(service.WithName("api"))
+	service.New("server").Run(blockUntilDone).Register(api)
+	workers := service.NewContainer(service.WithName("workers"))
+	stopErr := errors.New("queue not drained")
+	service.New("worker").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return stopErr
+	}).Register(workers)
+	require.NoError(t, api.StartAll(context.Background()))
+	require.NoError(t, workers.StartAll(context.Background()))
+
+	service.StopAll(api, workers)
+	err := service.WaitAll(context.Background(), api, workers)
+	assert.ErrorIs(t, err, stopErr)
+	assert.Equal(t, service.ContainerStopped, api.State())
+	assert.Equal(t, service.ContainerFailed, workers.State())
+}
+
+func TestWaitAll_notStarted(t *testing.T) {
+	api := service.NewContainer(service.WithName("api"))
+	service.New("server").Run(blockUntilDone).Register(api)
+	idle := service.NewContainer(service.WithName("idle"))
+	service.New("worker").Run(blockUntilDone).Register(idle)
+	require.NoError(t, api.StartAll(context.Background()))
+
+	assert.NotPanics(t, func() {
+		service.StopAll(api, idle)
+	})
+	assert.NoError(t, service.WaitAll(context.Background(), api, idle))
+	assert.Equal(t, service.ContainerStopped, api.State())
+	assert.Equal(t, service.ContainerCreated, idle.State())
+}
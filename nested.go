@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// EscalationPolicy defines how a parent container handles the failure of a child container, see RegisterChild
+type EscalationPolicy int
+
+const (
+	// EscalateStopParent stops all services of the parent container when the child container failed
+	EscalateStopParent EscalationPolicy = iota
+	// EscalateRestartChild starts the child container again after it failed, see WithRestartPolicy
+	EscalateRestartChild
+	// EscalateIgnore keeps the parent container running, the failed child container stays stopped
+	EscalateIgnore
+)
+
+func (p EscalationPolicy) String() string {
+	switch p {
+	case EscalateStopParent:
+		return "StopParent"
+	case EscalateRestartChild:
+		return "RestartChild"
+	case EscalateIgnore:
+		return "Ignore"
+	default:
+		return "Unknown"
+	}
+}
+
+var _ Runner = &Container{}
+
+// String returns the name of the container, so a container registered as service is named like the container
+func (c *Container) String() string {
+	return c.name
+}
+
+// Run starts all services with StartAll and blocks until the container stopped, so a container can be registered
+// as service of another container, see RegisterChild to control how failures are escalated.
+// Canceling ctx stops all services. Returns an error if the container failed to start or failed,
+// see ContainerFailed.
+func (c *Container) Run(ctx context.Context) error {
+	err := c.StartAll(ctx)
+	if err != nil {
+		c.WaitAllStopped(context.Background())
+		return fmt.Errorf("failed to start container '%s': %w", c.name, err)
+	}
+	c.mu.Lock()
+	shutdownDone := c.shutdownDone
+	c.mu.Unlock()
+	<-shutdownDone
+	c.WaitAllStopped(context.Background())
+
+	if c.State() != ContainerFailed || ctx.Err() != nil {
+		return nil
+	}
+	var errs []error
+	for name, err := range c.ServiceErrors() {
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+	return fmt.Errorf("container '%s' failed: %w", c.name, errors.Join(errs...))
+}
+
+// RegisterChild registers the child container as a service named like the child in the parent container.
+// The child is started with StartAll when the service runs and stopped with the parent container.
+// A failed child container, see ContainerFailed, is escalated to the parent according to the policy.
+// The backoff of EscalateRestartChild can be changed with WithRestartPolicy in opts.
+// Unlike Merge, the services of the child stay in the child container.
+func (c *Container) RegisterChild(child *Container, policy EscalationPolicy, opts ...RegisterOption) {
+	switch policy {
+	case EscalateRestartChild:
+		opts = append([]RegisterOption{WithRestartPolicy(RestartOnFailure, Backoff{})}, opts...)
+	case EscalateIgnore:
+		opts = append([]RegisterOption{WithCritical(false)}, opts...)
+	}
+	opts = append([]RegisterOption{WithServiceName(child.Name())}, opts...)
+	c.Register(&childService{child: child}, opts...)
+}
+
+// childService runs a child container inside the parent container, see RegisterChild
+type childService struct {
+	child *Container
+}
+
+func (s *childService) Run(ctx context.Context) error {
+	return s.child.Run(ctx)
+}
+
+// Health reports the health of the child container, see Container.Health
+func (s *childService) Health(ctx context.Context) error {
+	return s.child.Health(ctx).Err()
+}
@@ -0,0 +1,113 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFailingChild(runs *atomic.Int32) *service.Container {
+	child := service.NewContainer(service.WithName("child"))
+	service.New("worker").Run(func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			return errors.New("crashed")
+		}
+		<-ctx.Done()
+		return nil
+	}).Register(child)
+	return child
+}
+
+func TestRegisterChild_stopParent(t *testing.T) {
+	parent := service.NewContainer()
+	var runs atomic.Int32
+	parent.RegisterChild(newFailingChild(&runs), service.EscalateStopParent)
+	service.New("other").Run(blockUntilDone).Register(parent)
+	require.NoError(t, parent.StartAll(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	parent.WaitAllStopped(ctx)
+	require.NoError(t, ctx.Err())
+	assert.Equal(t, service.ContainerFailed, parent.State())
+	require.Error(t, parent.ServiceErrors()[parent.Name()+"/child"])
+	assert.Contains(t, parent.ServiceErrors()[parent.Name()+"/child"].Error(), "crashed")
+}
+
+func TestRegisterChild_restartChild(t *testing.T) {
+	parent := service.NewContainer()
+	var runs atomic.Int32
+	child := newFailingChild(&runs)
+	parent.RegisterChild(child, service.EscalateRestartChild,
+		service.WithRestartPolicy(service.RestartOnFailure, service.Backoff{Initial: time.Millisecond}))
+	require.NoError(t, parent.StartAll(context.Background()))
+
+	assert.Eventually(t, func() bool {
+		return runs.Load() == 2 && child.State() == service.ContainerRunning
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, service.ContainerRunning, parent.State())
+
+	parent.StopAll()
+	parent.WaitAllStopped(context.Background())
+	assert.Equal(t, service.ContainerStopped, child.State())
+}
+
+func TestRegisterChild_ignore(t *testing.T) {
+	parent := service.NewContainer()
+	var runs atomic.Int32
+	child := newFailingChild(&runs)
+	parent.RegisterChild(child, service.EscalateIgnore)
+	service.New("other").Run(blockUntilDone).Register(parent)
+	require.NoError(t, parent.StartAll(context.Background()))
+
+	assert.Eventually(t, func() bool {
+		return child.State() == service.ContainerFailed
+	}, time.Second, 5*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return parent.RunningCount() == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, service.ContainerRunning, parent.State())
+
+	parent.StopAll()
+	parent.WaitAllStopped(context.Background())
+}
+
+func TestContainer_Run(t *testing.T) {
+	app := service.NewContainer(service.WithName("app"))
+	infra := service.NewContainer(service.WithName("infra"))
+	var infraStopped atomic.Bool
+	service.New("db").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		infraStopped.Store(true)
+		return nil
+	}).Register(infra)
+	app.Register(infra)
+	service.New("api").Run(blockUntilDone).Register(app)
+	require.NoError(t, app.StartAll(context.Background()))
+
+	assert.Equal(t, []string{"infra", "api"}, app.ServiceNames())
+	require.Eventually(t, infra.IsRunning, time.Second, time.Millisecond)
+	require.NoError(t, app.StopAllAndWait(context.Background()))
+	assert.True(t, infraStopped.Load())
+	assert.Equal(t, service.ContainerStopped, infra.State())
+}
+
+func TestContainer_Run_failure(t *testing.T) {
+	app := service.NewContainer(service.WithName("app"))
+	var runs atomic.Int32
+	app.Register(newFailingChild(&runs))
+	service.New("api").Run(blockUntilDone).Register(app)
+	require.NoError(t, app.StartAll(context.Background()))
+
+	err := app.WaitAllStopped(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "container 'child' failed")
+	assert.Contains(t, err.Error(), "crashed")
+	assert.Equal(t, service.ContainerFailed, app.State())
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"sort"
+)
+
+// ReportOrder defines the order of services in ServiceNames, Status and log output
+type ReportOrder int
+
+const (
+	// RegistrationOrder lists services in the order they were registered
+	RegistrationOrder ReportOrder = iota
+	// StartOrder lists services in the order they were started, services that were never started come last
+	StartOrder
+)
+
+// WithReportOrder sets the order of services in ServiceNames, Status and log output.
+// Default is RegistrationOrder.
+func WithReportOrder(order ReportOrder) Option {
+	return func(c *Container) {
+		c.reportOrder = order
+	}
+}
+
+// orderedServices returns the registered services sorted by the configured ReportOrder.
+// c.mu must be held by the caller.
+func (c *Container) orderedServices() []*serviceInfo {
+	services := append([]*serviceInfo{}, c.services...)
+	if c.reportOrder == StartOrder {
+		sort.SliceStable(services, func(i, j int) bool {
+			return c.startSeq(services[i]) < c.startSeq(services[j])
+		})
+	}
+	return services
+}
+
+// orderedRunContexts returns the runContexts of all started services sorted by the configured ReportOrder.
+// c.mu must be held by the caller.
+func (c *Container) orderedRunContexts() []*runContext {
+	rcs := make([]*runContext, 0, len(c.runContexts))
+	for _, s := range c.orderedServices() {
+		if rc, ok := c.runContexts[s.name]; ok {
+			rcs = append(rcs, rc)
+		}
+	}
+	return rcs
+}
+
+// startSeq returns the position of the service in the start sequence, services that were not started come last
+// c.mu must be held by the caller.
+func (c *Container) startSeq(s *serviceInfo) int {
+	if rc, ok := c.runContexts[s.name]; ok {
+		return rc.seq
+	}
+	return int(^uint(0) >> 1)
+}
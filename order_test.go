@@ -0,0 +1,47 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statusNames(status []service.ServiceStatus) []string {
+	names := make([]string, 0, len(status))
+	for _, s := range status {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestReportOrder(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		order    service.ReportOrder
+		expected []string
+	}{
+		{name: "registration", order: service.RegistrationOrder, expected: []string{"a", "b", "c", "d", "e"}},
+		{name: "start", order: service.StartOrder, expected: []string{"a", "c", "d", "e", "b"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := service.NewContainer(service.WithReportOrder(tc.order), service.WithInitBarrier("db"))
+			service.New("a").Register(c)
+			service.New("b").InitAfter("db").Register(c)
+			service.New("c").Register(c)
+			service.New("d").InitBefore("db").Register(c)
+			service.New("e").Register(c)
+
+			err := c.StartAll(context.Background())
+			require.NoError(t, err)
+			c.WaitAllStopped(context.Background())
+
+			for i := 0; i < 10; i++ {
+				assert.Equal(t, tc.expected, c.ServiceNames())
+				assert.Equal(t, tc.expected, statusNames(c.Status()))
+			}
+		})
+	}
+}
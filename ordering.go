@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WithInitBarrier declares a named init barrier. Services can declare to be initialized before or after a barrier,
+// e.g. all services that need the database schema are initialized after the "schema-migrated" barrier
+// that the migration service is initialized before. See InitBarrierUser.
+func WithInitBarrier(name string) Option {
+	return func(c *Container) {
+		c.barriers = append(c.barriers, name)
+	}
+}
+
+// startOrder returns the services in the order they must be initialized and started.
+// Services are ordered by registration unless phases, dependencies or init barriers require a different order.
+func (c *Container) startOrder(services []*serviceInfo) ([]*serviceInfo, error) {
+	// The graph contains all services followed by all barriers as nodes
+	nodes := len(services) + len(c.barriers)
+	barrierIndex := map[string]int{}
+	for i, b := range c.barriers {
+		barrierIndex[b] = len(services) + i
+	}
+	edges := make([][]int, nodes)
+	inDegree := make([]int, nodes)
+	addEdge := func(from, to int) {
+		edges[from] = append(edges[from], to)
+		inDegree[to]++
+	}
+
+	serviceIndex := map[string]int{}
+	for i, s := range services {
+		serviceIndex[s.name] = i
+	}
+	for i, s := range services {
+		for _, d := range s.dependsOn {
+			di, ok := serviceIndex[d]
+			if !ok {
+				return nil, fmt.Errorf("service '%s' depends on unknown service '%s' in container '%s'", s.name, d, c.name)
+			}
+			addEdge(di, i)
+		}
+		// Every service of a lower phase is started before all services of the next higher phase
+		next, hasNext := 0, false
+		for _, o := range services {
+			if o.phase > s.phase && (!hasNext || o.phase < next) {
+				next, hasNext = o.phase, true
+			}
+		}
+		for j, o := range services {
+			if hasNext && o.phase == next {
+				addEdge(i, j)
+			}
+		}
+		for _, b := range s.initBefore {
+			bi, ok := barrierIndex[b]
+			if !ok {
+				return nil, fmt.Errorf("service '%s' uses unknown init barrier '%s' in container '%s'", s.name, b, c.name)
+			}
+			addEdge(i, bi)
+		}
+		for _, b := range s.initAfter {
+			bi, ok := barrierIndex[b]
+			if !ok {
+				return nil, fmt.Errorf("service '%s' uses unknown init barrier '%s' in container '%s'", s.name, b, c.name)
+			}
+			addEdge(bi, i)
+		}
+	}
+
+	// Kahn's algorithm, always picking the ready node that was registered first to keep the order stable
+	ordered := make([]*serviceInfo, 0, len(services))
+	var ready []int
+	for n := 0; n < nodes; n++ {
+		if inDegree[n] == 0 {
+			ready = append(ready, n)
+		}
+	}
+	visited := 0
+	for len(ready) > 0 {
+		slices.Sort(ready)
+		n := ready[0]
+		ready = ready[1:]
+		visited++
+		if n < len(services) {
+			ordered = append(ordered, services[n])
+		}
+		for _, next := range edges[n] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	if visited < nodes {
+		var cycle []string
+		for i, s := range services {
+			if inDegree[i] > 0 {
+				cycle = append(cycle, s.name)
+			}
+		}
+		return nil, fmt.Errorf("cyclic init order in container '%s' between services: %s", c.name, strings.Join(cycle, ", "))
+	}
+	return ordered, nil
+}
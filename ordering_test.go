@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initRecorder returns an InitFunc that records the name in order
+func initRecorder(order *[]string, name string) service.InitFunc {
+	return func(ctx context.Context) error {
+		*order = append(*order, name)
+		return nil
+	}
+}
+
+func TestInitBarrier(t *testing.T) {
+	c := service.NewContainer(service.WithInitBarrier("schema-migrated"))
+	var order []string
+
+	service.New("api").Init(initRecorder(&order, "api")).InitAfter("schema-migrated").Register(c)
+	service.New("cache").Init(initRecorder(&order, "cache")).Register(c)
+	service.New("migration").Init(initRecorder(&order, "migration")).InitBefore("schema-migrated").Register(c)
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	assert.Equal(t, []string{"cache", "migration", "api"}, order)
+}
+
+func TestInitBarrier_unknown(t *testing.T) {
+	c := service.NewContainer()
+	service.New("api").InitAfter("schema-migrated").Register(c)
+
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown init barrier 'schema-migrated'")
+}
+
+func TestInitBarrier_cycle(t *testing.T) {
+	c := service.NewContainer(service.WithInitBarrier("a"), service.WithInitBarrier("b"))
+	service.New("s1").InitBefore("a").InitAfter("b").Register(c)
+	service.New("s2").InitBefore("b").InitAfter("a").Register(c)
+
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cyclic init order")
+}
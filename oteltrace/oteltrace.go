@@ -0,0 +1,52 @@
+// Package oteltrace creates OpenTelemetry spans for the lifecycle of a service.Container:
+//
+//	c := service.NewContainer(service.WithTracer(oteltrace.New(otel.GetTracerProvider())))
+//
+// It is a separate module, so the core package does not depend on OpenTelemetry.
+package oteltrace
+
+import (
+	"context"
+	"sort"
+
+	"github.com/niondir/go-service"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the OpenTelemetry tracer
+const instrumentationName = "github.com/niondir/go-service"
+
+var _ service.Tracer = &Tracer{}
+
+// Tracer implements service.Tracer with an OpenTelemetry trace.TracerProvider
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// New creates a tracer for the given provider, see service.WithTracer
+func New(tp trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: tp.Tracer(instrumentationName)}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, attrs map[string]string) (context.Context, func(err error)) {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, attribute.String(k, attrs[k]))
+	}
+
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(kvs...))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,44 @@
+package oteltrace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/niondir/go-service/oteltrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	c := service.NewContainer(service.WithTracer(oteltrace.New(tp)))
+	initErr := errors.New("init failed")
+	service.New("db").Init(func(ctx context.Context) error {
+		return initErr
+	}).Register(c)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "deploy")
+	err := c.StartAll(ctx)
+	parent.End()
+	require.ErrorIs(t, err, initErr)
+	c.WaitAllStopped(context.Background())
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	require.Contains(t, spans, service.SpanStartAll)
+	require.Contains(t, spans, service.SpanInit)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[service.SpanStartAll].Parent().SpanID())
+	assert.Equal(t, spans[service.SpanStartAll].SpanContext().SpanID(), spans[service.SpanInit].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[service.SpanInit].Status().Code)
+	assert.Contains(t, spans[service.SpanInit].Attributes(), attribute.String(service.AttrService, "db"))
+}
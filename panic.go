@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned for panics recovered by the container, e.g. in RunOnce or Container.Health.
+// Use errors.As to distinguish panics from regular errors and to access the stack.
+type PanicError struct {
+	// Value passed to panic
+	Value any
+	// Stack of the panicking goroutine
+	Stack []byte
+}
+
+// newPanicError must be called from the deferred function that recovered the panic to capture the stack
+func newPanicError(v any) *PanicError {
+	return &PanicError{Value: v, Stack: debug.Stack()}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// WithRecoverPanics recovers panics in Init and Run of all services, see WithPanicRecovery
+func WithRecoverPanics() Option {
+	return func(c *Container) {
+		c.recoverPanics = true
+	}
+}
+
+// WithPanicRecovery recovers panics in Init and Run of the service.
+// A recovered panic is returned as *PanicError, reported in Container.ServiceErrors and handled like
+// any other error, e.g. it stops the container, instead of crashing the process.
+func WithPanicRecovery() RegisterOption {
+	return func(s *serviceInfo) {
+		s.recoverPanics = true
+	}
+}
+
+// callService calls f and recovers panics when panic recovery is enabled for the service or container
+func (c *Container) callService(s *serviceInfo, f func() error) (err error) {
+	if !c.recoverPanics && !s.recoverPanics {
+		return f()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			pe := newPanicError(r)
+			c.serviceLogger(s).Error("Recovered panic in service", "panic", r, "stack", string(pe.Stack))
+			err = pe
+		}
+	}()
+	return f()
+}
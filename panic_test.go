@@ -0,0 +1,42 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRecoverPanics_run(t *testing.T) {
+	c := service.NewContainer(service.WithRecoverPanics())
+	service.New("panicking").Run(func(ctx context.Context) error {
+		panic("boom")
+	}).Register(c)
+	service.New("other").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	c.WaitAllStopped(context.Background())
+
+	err := c.ServiceErrors()[c.Name()+"/panicking"]
+	var pe *service.PanicError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "boom", pe.Value)
+	assert.NotEmpty(t, pe.Stack)
+}
+
+func TestWithPanicRecovery_init(t *testing.T) {
+	c := service.NewContainer()
+	initErr := errors.New("init failed")
+	service.New("panicking").Init(func(ctx context.Context) error {
+		panic(initErr)
+	}).Run(blockUntilDone).RecoverPanics().Register(c)
+
+	err := c.StartAll(context.Background())
+	var pe *service.PanicError
+	require.ErrorAs(t, err, &pe)
+	assert.ErrorIs(t, err, initErr)
+	assert.ErrorAs(t, c.ServiceErrors()[c.Name()+"/panicking"], &pe)
+	c.WaitAllStopped(context.Background())
+}
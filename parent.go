@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithStopOnParentCancelDelay delays stopping the services by the given grace period after the parent context
+// passed to StartAll was canceled. During the grace period the container is draining: services keep running,
+// but the container reports not to be ready, so load balancers can de-register the application before it stops.
+// Calling StopAll or a failing service still stops all services immediately.
+func WithStopOnParentCancelDelay(d time.Duration) Option {
+	return func(c *Container) {
+		c.parentCancelDelay = d
+	}
+}
+
+// IsDraining returns true if the parent context was canceled and the services are going to be stopped,
+// see WithStopOnParentCancelDelay
+func (c *Container) IsDraining() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.draining
+}
+
+// OnParentContextCanceled registers a callback that is called when the parent context passed to StartAll is canceled,
+// e.g. because the orchestrator requested the shutdown. It is not called when the container is stopped by StopAll
+// or a failing service. The cause of the parent context is passed to the callback, see context.Cause.
+func (c *Container) OnParentContextCanceled(f func(cause error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.parentCanceledCallbacks = append(c.parentCanceledCallbacks, f)
+}
+
+// onParentCanceled is called when the parent context of the run context is canceled
+func (c *Container) onParentCanceled(cause error) {
+	c.mu.Lock()
+	c.draining = true
+	runCtx, cancel := c.runCtx, c.runCtxCancel
+	callbacks := append([]func(cause error){}, c.parentCanceledCallbacks...)
+	c.mu.Unlock()
+	c.notifyStatusChange()
+
+	if runCtx.Err() == nil {
+		c.beginShutdown(fmt.Sprintf("parent context canceled: %v", cause))
+		c.log.Info("Shutdown requested by parent context", "cause", cause, "container", c.name, "run", c.RunID())
+		for _, f := range callbacks {
+			c.callSafe("parent context canceled callback", func() {
+				f(cause)
+			})
+		}
+	}
+
+	if c.parentCancelDelay > 0 {
+		c.log.Info("Parent context canceled, draining before services are stopped",
+			"delay", c.parentCancelDelay, "container", c.name, "run", c.RunID())
+		select {
+		case <-runCtx.Done():
+		case <-time.After(c.parentCancelDelay):
+		}
+	}
+	cancel(fmt.Errorf("parent context canceled: %w", cause))
+}
@@ -0,0 +1,95 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopOnParentCancelDelay(t *testing.T) {
+	c := service.NewContainer(service.WithStopOnParentCancelDelay(100 * time.Millisecond))
+	s1 := &testService{Name: "s1"}
+	c.Register(s1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := c.StartAll(ctx)
+	require.NoError(t, err)
+	<-s1.startedCh
+	assert.False(t, c.IsDraining())
+	assert.True(t, c.WaitAllRunningTimeout(time.Second))
+
+	cancel()
+	assert.Eventually(t, c.IsDraining, time.Second, time.Millisecond)
+	assert.False(t, c.WaitAllRunningTimeout(time.Second), "draining container must not be ready")
+	assert.Equal(t, 1, c.RunningCount(), "services keep running while draining")
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), time.Second)
+	defer cancelShutdown()
+	c.WaitAllStopped(shutdownCtx)
+	assertServiceStartedAndStopped(t, s1)
+}
+
+func TestStopOnParentCancelDelay_stopAll(t *testing.T) {
+	c := service.NewContainer(service.WithStopOnParentCancelDelay(time.Hour))
+	s1 := &testService{Name: "s1"}
+	c.Register(s1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := c.StartAll(ctx)
+	require.NoError(t, err)
+
+	cancel()
+	assert.Eventually(t, c.IsDraining, time.Second, time.Millisecond)
+	c.StopAll()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), time.Second)
+	defer cancelShutdown()
+	c.WaitAllStopped(shutdownCtx)
+	assertServiceStartedAndStopped(t, s1)
+}
+
+func TestOnParentContextCanceled(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(&testService{Name: "s1"})
+	causes := make(chan error, 1)
+	c.OnParentContextCanceled(func(cause error) {
+		causes <- cause
+	})
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	err := c.StartAll(ctx)
+	require.NoError(t, err)
+
+	orchestrator := errors.New("SIGTERM")
+	cancel(orchestrator)
+	c.WaitAllStopped(context.Background())
+	select {
+	case cause := <-causes:
+		assert.Equal(t, orchestrator, cause)
+	case <-time.After(time.Second):
+		t.Fatal("callback not called")
+	}
+}
+
+func TestOnParentContextCanceled_notCalledOnStopAll(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(&testService{Name: "s1"})
+	called := false
+	c.OnParentContextCanceled(func(cause error) {
+		called = true
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := c.StartAll(ctx)
+	require.NoError(t, err)
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, called)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"slices"
+)
+
+// StartOption configures a single StartAll call
+type StartOption func(o *startOptions)
+
+type startOptions struct {
+	profiles []string
+}
+
+// WithProfiles starts only the services tagged with any of the given profiles, e.g. to run different roles
+// from a single binary, see WithTags. Services without tags are started in all profiles, services the started
+// services depend on are started as well, see Dependent. Other services stay registered but are not started.
+func WithProfiles(profiles ...string) StartOption {
+	return func(o *startOptions) {
+		o.profiles = append(o.profiles, profiles...)
+	}
+}
+
+// inProfiles returns true when the service is started with the given profiles, ignoring dependencies
+func inProfiles(s *serviceInfo, profiles []string) bool {
+	if len(profiles) == 0 || len(s.tags) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(s.tags, func(tag string) bool {
+		return slices.Contains(profiles, tag)
+	})
+}
+
+// selectProfiles returns the services started with the given profiles including their dependencies
+func selectProfiles(services []*serviceInfo, profiles []string) []*serviceInfo {
+	if len(profiles) == 0 {
+		return services
+	}
+	byName := map[string]*serviceInfo{}
+	for _, s := range services {
+		byName[s.name] = s
+	}
+	selected := map[string]bool{}
+	var add func(s *serviceInfo)
+	add = func(s *serviceInfo) {
+		if selected[s.name] {
+			return
+		}
+		selected[s.name] = true
+		for _, d := range s.dependsOn {
+			if dep, ok := byName[d]; ok {
+				add(dep)
+			}
+		}
+	}
+	for _, s := range services {
+		if inProfiles(s, profiles) {
+			add(s)
+		}
+	}
+	return slices.DeleteFunc(slices.Clone(services), func(s *serviceInfo) bool {
+		return !selected[s.name]
+	})
+}
+
+// exclude marks a registered service as not started in the current run
+func (c *Container) exclude(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.excluded[name] = true
+}
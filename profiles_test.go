@@ -0,0 +1,48 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRoleContainer() *service.Container {
+	c := service.NewContainer()
+	service.New("db").Run(blockUntilDone).Register(c)
+	service.New("cache").Run(blockUntilDone).Tags("internal").Register(c)
+	service.New("api").Run(blockUntilDone).Tags("api").DependsOn("cache").Register(c)
+	service.New("worker").Run(blockUntilDone).Tags("worker").Register(c)
+	service.New("scheduler").Run(blockUntilDone).Tags("worker", "cron").Register(c)
+	return c
+}
+
+func TestWithProfiles(t *testing.T) {
+	c := newRoleContainer()
+	require.NoError(t, c.StartAll(context.Background(), service.WithProfiles("worker")))
+	assert.ElementsMatch(t, []string{"db", "worker", "scheduler"}, c.ServiceNames())
+	state, err := c.ServiceState("api")
+	require.NoError(t, err)
+	assert.Equal(t, service.StateRegistered, state)
+
+	// Services registered while running are only started when they are part of the profiles
+	service.New("late-api").Run(blockUntilDone).Tags("api").Register(c)
+	service.New("late-worker").Run(blockUntilDone).Tags("worker").Register(c)
+	assert.False(t, c.IsServiceRunning("late-api"))
+	assert.True(t, c.IsServiceRunning("late-worker"))
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
+
+func TestWithProfiles_dependencies(t *testing.T) {
+	c := newRoleContainer()
+	require.NoError(t, c.StartAll(context.Background(), service.WithProfiles("api")))
+	assert.ElementsMatch(t, []string{"db", "cache", "api"}, c.ServiceNames())
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+
+	// Without profiles all services are started
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Len(t, c.ServiceNames(), 5)
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// StartAllAndWaitReady starts all services like StartAll and blocks until all services are running and ready.
+// Readiness is reported by services implementing Readier or ReadyWaiter, see WaitAllReady.
+// The ctx is used as parent for the run context like in StartAll, when it expires before all services are ready,
+// the context error is returned.
+// When a service fails during startup the container is stopped and the error is returned.
+func (c *Container) StartAllAndWaitReady(ctx context.Context, opts ...StartOption) error {
+	err := c.StartAll(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	return c.waitReady(ctx)
+}
+
+// FromReadyWaiter adapts a legacy ReadyWaiter to the context aware Readier interface.
+// WaitReady can not be canceled, so it is called repeatedly with a timeout of at most readyWaiterPoll
+// until the service is ready or ctx is done. The timeout is shortened to the deadline of ctx.
+func FromReadyWaiter(w ReadyWaiter) Readier {
+	return readyWaiterAdapter{w}
+}
+
+// readyWaiterPoll limits the time a WaitReady call outlives the context of Ready
+const readyWaiterPoll = 100 * time.Millisecond
+
+type readyWaiterAdapter struct {
+	w ReadyWaiter
+}
+
+func (a readyWaiterAdapter) Ready(ctx context.Context) error {
+	for {
+		timeout := readyWaiterPoll
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = min(timeout, time.Until(deadline))
+		}
+		if timeout <= 0 {
+			return context.DeadlineExceeded
+		}
+		ready := make(chan bool, 1)
+		go func() {
+			ready <- a.w.WaitReady(timeout)
+		}()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ok := <-ready:
+			if ok {
+				return nil
+			}
+		}
+	}
+}
+
+// WithAutoReadyDelay lets services implementing neither Readier nor ReadyWaiter become ready
+// the given delay after their Run method was called. Default is 0, they are ready as soon as they run.
+func WithAutoReadyDelay(d time.Duration) Option {
+	return func(c *Container) {
+		c.autoReadyDelay = d
+	}
+}
+
+// autoReadier is ready at the given time, see WithAutoReadyDelay
+type autoReadier time.Time
+
+func (r autoReadier) Ready(ctx context.Context) error {
+	t := time.NewTimer(time.Until(time.Time(r)))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// readierOf returns the Readier of a service, legacy ReadyWaiter implementations are adapted.
+// Returns nil for services without readiness interface.
+func readierOf(service Runner) Readier {
+	if r, ok := service.(Readier); ok {
+		return r
+	}
+	if w, ok := service.(ReadyWaiter); ok {
+		return FromReadyWaiter(w)
+	}
+	return nil
+}
+
+// readierFor returns the Readier of a running service, see readierOf and WithAutoReadyDelay.
+// Returns nil when the service is already ready.
+func (c *Container) readierFor(rc *runContext) Readier {
+	readier := readierOf(rc.service.service)
+	if readier != nil {
+		return readier
+	}
+	c.mu.Lock()
+	readyAt := rc.startedAt.Add(c.autoReadyDelay)
+	c.mu.Unlock()
+	if !time.Now().Before(readyAt) {
+		return nil
+	}
+	return autoReadier(readyAt)
+}
+
+// waitDependencies blocks until all services the given service depends on are ready, see Dependent.
+// Returns an error when ctx is done or a dependency stopped before it was ready.
+func (c *Container) waitDependencies(ctx context.Context, s *serviceInfo) error {
+	for _, dep := range s.dependsOn {
+		c.mu.Lock()
+		rc, ok := c.runContexts[dep]
+		c.mu.Unlock()
+		if !ok {
+			return fmt.Errorf("dependency '%s' of service '%s' is not running in container '%s'", dep, s.name, c.name)
+		}
+		c.mu.Lock()
+		failed := rc.state == StateFailed
+		c.mu.Unlock()
+		if failed {
+			return fmt.Errorf("dependency '%s' of service '%s' failed", dep, s.name)
+		}
+		readier := c.readierFor(rc)
+		if readier == nil {
+			continue
+		}
+		depCtx, cancel := context.WithCancelCause(ctx)
+		go func() {
+			select {
+			case <-rc.done:
+				cancel(fmt.Errorf("dependency '%s' stopped", dep))
+			case <-depCtx.Done():
+			}
+		}()
+		err := readier.Ready(depCtx)
+		if err != nil {
+			if cause := context.Cause(depCtx); cause != nil {
+				err = cause
+			}
+			err = fmt.Errorf("dependency '%s' of service '%s' not ready: %w", dep, s.name, err)
+		}
+		cancel(nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WaitAllReady blocks until all running services are ready, the ctx is done or the container stopped.
+// Readiness is reported by services implementing Readier or the legacy ReadyWaiter,
+// all other services are ready as soon as they run.
+// Returns nil when all services are ready.
+func (c *Container) WaitAllReady(ctx context.Context) error {
+	return c.waitReady(ctx)
+}
+
+// waitReady blocks until all running services are ready, the ctx is done or the container stopped
+func (c *Container) waitReady(ctx context.Context) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("container '%s' is not running", c.name)
+	}
+	if c.IsDraining() {
+		return fmt.Errorf("container '%s' is draining", c.name)
+	}
+
+	c.mu.Lock()
+	runCtx := c.runCtx
+	c.mu.Unlock()
+	// Cancel the Ready calls that are still pending when waitReady returns
+	readyCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	mu := sync.Mutex{}
+	var notReady []error
+	wg := sync.WaitGroup{}
+	for _, rc := range c.runningServices() {
+		readier := c.readierFor(rc)
+		if readier == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := readier.Ready(readyCtx); err != nil {
+				mu.Lock()
+				notReady = append(notReady, fmt.Errorf("service '%s' not ready: %w", rc.service.name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	doneChan := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(doneChan)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for services in container '%s' to become ready: %w", c.name, ctx.Err())
+	case <-runCtx.Done():
+		return c.stoppedBeforeReadyErr()
+	case <-doneChan:
+	}
+
+	if runCtx.Err() != nil {
+		return c.stoppedBeforeReadyErr()
+	}
+	if c.IsDraining() {
+		return fmt.Errorf("container '%s' is draining", c.name)
+	}
+	if len(notReady) > 0 {
+		return errors.Join(notReady...)
+	}
+	return nil
+}
+
+func (c *Container) stoppedBeforeReadyErr() error {
+	errs := []error{fmt.Errorf("container '%s' stopped before all services were ready", c.name)}
+	serviceErrs := c.ServiceErrors()
+	names := make([]string, 0, len(serviceErrs))
+	for name := range serviceErrs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		errs = append(errs, fmt.Errorf("%s: %w", name, serviceErrs[name]))
+	}
+	return errors.Join(errs...)
+}
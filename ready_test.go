@@ -0,0 +1,241 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ service.ReadyWaiter = &readyService{}
+
+// readyService becomes ready after the given delay
+type readyService struct {
+	delay time.Duration
+	ready chan struct{}
+}
+
+func newReadyService(delay time.Duration) *readyService {
+	return &readyService{delay: delay, ready: make(chan struct{})}
+}
+
+func (s *readyService) Run(ctx context.Context) error {
+	time.Sleep(s.delay)
+	close(s.ready)
+	<-ctx.Done()
+	return nil
+}
+
+func (s *readyService) WaitReady(timeout time.Duration) bool {
+	select {
+	case <-s.ready:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func TestStartAllAndWaitReady(t *testing.T) {
+	c := service.NewContainer()
+	s := newReadyService(50 * time.Millisecond)
+	c.Register(s)
+
+	err := c.StartAllAndWaitReady(context.Background())
+	require.NoError(t, err)
+	select {
+	case <-s.ready:
+	default:
+		t.Fatal("expected service to be ready")
+	}
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestStartAllAndWaitReady_serviceFails(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(newReadyService(time.Second))
+	runErr := errors.New("failed to listen")
+	service.New("failing").Run(func(ctx context.Context) error {
+		return runErr
+	}).Register(c)
+
+	err := c.StartAllAndWaitReady(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, runErr)
+	c.WaitAllStopped(context.Background())
+}
+
+// ctxReadyService implements the context aware Readier
+type ctxReadyService struct {
+	*readyService
+}
+
+func (s ctxReadyService) Ready(ctx context.Context) error {
+	select {
+	case <-s.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestWaitAllReady(t *testing.T) {
+	c := service.NewContainer()
+	legacy := newReadyService(20 * time.Millisecond)
+	c.Register(legacy)
+	s := ctxReadyService{newReadyService(50 * time.Millisecond)}
+	c.Register(s)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, c.WaitAllReady(ctx))
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestWaitAllReady_timeout(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(newReadyService(time.Second))
+	require.NoError(t, c.StartAll(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Error(t, c.WaitAllReady(ctx))
+	assert.False(t, c.WaitAllRunningTimeout(20*time.Millisecond))
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestFromReadyWaiter(t *testing.T) {
+	s := newReadyService(0)
+	close(s.ready)
+	r := service.FromReadyWaiter(s)
+	assert.NoError(t, r.Ready(context.Background()))
+
+	r = service.FromReadyWaiter(newReadyService(0))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, r.Ready(ctx))
+}
+
+// timeoutRecorder is a ReadyWaiter that is never ready and records the timeouts it was called with
+type timeoutRecorder struct {
+	mu       sync.Mutex
+	timeouts []time.Duration
+}
+
+func (r *timeoutRecorder) WaitReady(timeout time.Duration) bool {
+	r.mu.Lock()
+	r.timeouts = append(r.timeouts, timeout)
+	r.mu.Unlock()
+	time.Sleep(timeout)
+	return false
+}
+
+func TestFromReadyWaiter_withoutDeadline(t *testing.T) {
+	w := &timeoutRecorder{}
+	r := service.FromReadyWaiter(w)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		cancel()
+	}()
+	assert.ErrorIs(t, r.Ready(ctx), context.Canceled)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	require.NotEmpty(t, w.timeouts)
+	for _, timeout := range w.timeouts {
+		assert.LessOrEqual(t, timeout, 100*time.Millisecond, "WaitReady must not outlive the context")
+	}
+}
+
+type waiterKey struct{}
+
+// blockingReadier is never ready and signals canceled when the context of a Ready call by WaitAllReady is done,
+// see waiterKey
+type blockingReadier struct {
+	canceled chan struct{}
+}
+
+func (s *blockingReadier) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (s *blockingReadier) Ready(ctx context.Context) error {
+	<-ctx.Done()
+	if ctx.Value(waiterKey{}) != nil {
+		close(s.canceled)
+	}
+	return ctx.Err()
+}
+
+func TestWaitAllReady_cancelsPendingReady(t *testing.T) {
+	c := service.NewContainer()
+	s := &blockingReadier{canceled: make(chan struct{})}
+	c.Register(s)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		c.StopAll()
+	}()
+	assert.Error(t, c.WaitAllReady(context.WithValue(context.Background(), waiterKey{}, true)))
+	select {
+	case <-s.canceled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the pending Ready call to be canceled")
+	}
+	c.WaitAllStopped(context.Background())
+}
+
+func TestWithAutoReadyDelay(t *testing.T) {
+	c := service.NewContainer(service.WithAutoReadyDelay(50 * time.Millisecond))
+	service.New("plain").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, c.WaitAllReady(ctx), "plain service must not be ready before the delay")
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, c.WaitAllReady(ctx))
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestWithStartupTimeout(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(ctxReadyService{newReadyService(200 * time.Millisecond)}, service.WithStartupTimeout(20*time.Millisecond))
+	require.NoError(t, c.StartAll(context.Background()))
+
+	err := c.WaitAllStopped(context.Background())
+	assert.ErrorIs(t, err, service.ErrStartupTimeout)
+	assert.True(t, c.Status()[0].ReadyAt.IsZero())
+}
+
+func TestStatus_readyAt(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(ctxReadyService{newReadyService(20 * time.Millisecond)}, service.WithStartupTimeout(time.Second))
+	require.NoError(t, c.StartAllAndWaitReady(context.Background()))
+
+	assert.Eventually(t, func() bool {
+		return !c.Status()[0].ReadyAt.IsZero()
+	}, time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, c.Status()[0].StartupDuration, 20*time.Millisecond)
+	assert.NoError(t, c.StopAllAndWait(context.Background()))
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"time"
+)
+
+// RegisterOption configures a single service when it is registered, see Container.Register.
+// Options are applied after the optional interfaces of the service are evaluated and take precedence.
+// Defaults set with WithServiceDefaults are applied first and can be overridden by both.
+type RegisterOption func(s *serviceInfo)
+
+// WithServiceName overrides the name of the service, by default the name is derived from fmt.Stringer or the type
+func WithServiceName(name string) RegisterOption {
+	return func(s *serviceInfo) {
+		s.name = name
+	}
+}
+
+// WithTags adds tags to the service, see Tagger and WithProfiles
+func WithTags(tags ...string) RegisterOption {
+	return func(s *serviceInfo) {
+		s.tags = append(append([]string{}, s.tags...), tags...)
+	}
+}
+
+// WithCritical sets if a failing Init or Run stops the container, see CriticalityReporter
+func WithCritical(critical bool) RegisterOption {
+	return func(s *serviceInfo) {
+		s.critical = critical
+	}
+}
+
+// WithPhase sets the startup phase of the service, see Phaser
+func WithPhase(phase int) RegisterOption {
+	return func(s *serviceInfo) {
+		s.phase = phase
+	}
+}
+
+// WithDependsOn declares services that must be started before the service, see Dependent
+func WithDependsOn(services ...string) RegisterOption {
+	return func(s *serviceInfo) {
+		s.dependsOn = append(append([]string{}, s.dependsOn...), services...)
+	}
+}
+
+// WithShutdownPriority sets the priority used to order the shutdown, see ShutdownPrioritizer
+func WithShutdownPriority(p int) RegisterOption {
+	return func(s *serviceInfo) {
+		s.shutdownPriority = p
+	}
+}
+
+// WithInitBefore initializes the service before the given init barriers, see InitBarrierUser
+func WithInitBefore(barriers ...string) RegisterOption {
+	return func(s *serviceInfo) {
+		s.initBefore = append(append([]string{}, s.initBefore...), barriers...)
+	}
+}
+
+// WithInitAfter initializes the service after the given init barriers, see InitBarrierUser
+func WithInitAfter(barriers ...string) RegisterOption {
+	return func(s *serviceInfo) {
+		s.initAfter = append(append([]string{}, s.initAfter...), barriers...)
+	}
+}
+
+// WithServiceDefaults sets options that are applied to every service registered afterwards,
+// e.g. a stop timeout or the criticality. The optional interfaces of a service and the options passed
+// to Register or the Builder override the defaults.
+func WithServiceDefaults(opts ...RegisterOption) Option {
+	return func(c *Container) {
+		c.serviceDefaults = append(c.serviceDefaults, opts...)
+	}
+}
+
+// WithStopTimeout limits the time the shutdown waits for the service to return from Run after its context
+// was canceled. When the timeout is exceeded a warning is logged and the shutdown continues with the services
+// of lower shutdown priority. WaitAllStopped still waits for the service. Default is 0, no timeout.
+func WithStopTimeout(d time.Duration) RegisterOption {
+	return func(s *serviceInfo) {
+		s.stopTimeout = d
+	}
+}
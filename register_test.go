@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister_options(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(service.WithRunFunc(blockUntilDone),
+		service.WithServiceName("worker"),
+		service.WithTags("queue"),
+	)
+
+	status := c.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, "worker", status[0].Name)
+	assert.Equal(t, []string{"queue"}, status[0].Tags)
+}
+
+func TestRegister_stopTimeout(t *testing.T) {
+	c := service.NewContainer()
+	release := make(chan struct{})
+	var lowStopped time.Time
+	service.New("low").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		lowStopped = time.Now()
+		return nil
+	}).Register(c)
+	c.Register(service.WithRunFunc(func(ctx context.Context) error {
+		<-release
+		return nil
+	}), service.WithServiceName("stuck"), service.WithShutdownPriority(10), service.WithStopTimeout(20*time.Millisecond))
+
+	require.NoError(t, c.StartAll(context.Background()))
+	c.StopAll()
+	time.Sleep(100 * time.Millisecond)
+	released := time.Now()
+	close(release)
+	c.WaitAllStopped(context.Background())
+	assert.True(t, lowStopped.Before(released), "low priority service must be stopped after the stop timeout")
+}
+
+func TestWithServiceDefaults(t *testing.T) {
+	c := service.NewContainer(service.WithServiceDefaults(service.WithCritical(false), service.WithTags("default")))
+	failing := errors.New("failed")
+	service.New("optional").Run(func(ctx context.Context) error {
+		return failing
+	}).Register(c)
+	service.New("main").Run(blockUntilDone).Critical(true).Tags("main").Register(c)
+
+	status := c.Status()
+	assert.Equal(t, []string{"default", "main"}, status[1].Tags)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, c.RunningCount(), "non-critical default must keep main running")
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestWithCritical_initFails(t *testing.T) {
+	c := service.NewContainer()
+	initErr := errors.New("cache unreachable")
+	service.New("cache-warmer").Init(func(ctx context.Context) error {
+		return initErr
+	}).Run(blockUntilDone).With(service.WithCritical(false)).Register(c)
+	service.New("main").Run(blockUntilDone).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Equal(t, 1, c.RunningCount(), "main must run")
+	assert.Len(t, c.StartReport().Warnings, 1)
+	state, err := c.ServiceState("cache-warmer")
+	require.NoError(t, err)
+	assert.Equal(t, service.StateFailed, state)
+
+	err = c.StopAllAndWait(context.Background())
+	assert.ErrorIs(t, err, initErr)
+	assert.Equal(t, service.ContainerStopped, c.State())
+}
+
+func TestWithCritical_initFailsDependent(t *testing.T) {
+	c := service.NewContainer()
+	service.New("cache").Init(func(ctx context.Context) error {
+		return errors.New("cache unreachable")
+	}).Run(blockUntilDone).With(service.WithCritical(false)).Register(c)
+	service.New("api").DependsOn("cache").Run(blockUntilDone).Register(c)
+
+	assert.Error(t, c.StartAll(context.Background()))
+	c.WaitAllStopped(context.Background())
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// WithAfterStop calls f after Run of the service returned and the service is stopped or failed,
+// e.g. to release big caches. f is not called when Run is restarted, see WithRestartPolicy.
+// Waiting for the service to stop, e.g. by Container.Stop or the shutdown, includes waiting for f.
+func WithAfterStop(f func()) RegisterOption {
+	return func(s *serviceInfo) {
+		s.afterStop = append(s.afterStop, f)
+	}
+}
+
+// WithFreeOSMemory returns memory to the operating system after the service stopped, see debug.FreeOSMemory.
+// Intended for services holding large amounts of memory, so the memory is released promptly while
+// the other services of the container keep running.
+func WithFreeOSMemory() RegisterOption {
+	return WithAfterStop(debug.FreeOSMemory)
+}
+
+// runAfterStop calls the WithAfterStop hooks of the service
+func (c *Container) runAfterStop(s *serviceInfo) {
+	for _, f := range s.afterStop {
+		start := time.Now()
+		c.callSafe("after stop hook", f, "name", s.name)
+		c.serviceLogger(s).Debug("Called after stop hook", "duration", time.Since(start))
+	}
+}
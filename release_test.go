@@ -0,0 +1,50 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAfterStop(t *testing.T) {
+	var runs, hooks atomic.Int32
+	c := service.NewContainer()
+	service.New("cache").Run(func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			return errors.New("restart")
+		}
+		<-ctx.Done()
+		return nil
+	}).Restart(service.RestartOnFailure, service.Backoff{Initial: time.Millisecond}).AfterStop(func() {
+		hooks.Add(1)
+	}).FreeOSMemory().Register(c)
+	service.New("api").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	require.Eventually(t, func() bool {
+		return runs.Load() == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(0), hooks.Load(), "not called on restart")
+
+	require.NoError(t, c.Stop(context.Background(), "cache"))
+	assert.Equal(t, int32(1), hooks.Load())
+	assert.True(t, c.IsServiceRunning("api"))
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+	assert.Equal(t, int32(1), hooks.Load())
+}
+
+func TestWithAfterStop_panic(t *testing.T) {
+	c := service.NewContainer()
+	service.New("cache").Run(blockUntilDone).AfterStop(func() {
+		panic("bug in hook")
+	}).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+	assert.Equal(t, service.ContainerStopped, c.State())
+}
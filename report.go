@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StartReport describes how the services of a container were started by StartAll
+type StartReport struct {
+	Container string
+	// RunID of the run that was started, see RunInfo
+	RunID     string
+	StartedAt time.Time
+	// Duration of the whole start sequence
+	Duration time.Duration
+	// Services in the order they were initialized
+	Services []ServiceStartInfo
+	// RunOrder contains the names of the services in the order their Run method was called by StartAll
+	RunOrder []string
+	// Skipped contains the names of services that were not started
+	Skipped []string
+	// Warnings collected during startup that did not prevent the start
+	Warnings []string
+	// Err is the error returned by StartAll
+	Err error
+}
+
+// ServiceStartInfo describes the start of a single service
+type ServiceStartInfo struct {
+	Name string
+	// Order is the zero based position in the start sequence
+	Order        int
+	InitDuration time.Duration
+	// Err is the error returned by Init
+	Err error
+	// DependencyWait is the time spent waiting for the services it depends on to become ready, see Dependent
+	DependencyWait time.Duration
+	// RunAt is the time Run was called, zero if the service was not run
+	RunAt time.Time
+}
+
+func (r *StartReport) String() string {
+	sb := strings.Builder{}
+	status := "started"
+	if r.Err != nil {
+		status = "failed to start"
+	}
+	sb.WriteString(fmt.Sprintf("Container '%s' (run %s) %s in %s\n", r.Container, r.RunID, status, r.Duration))
+	for _, s := range r.Services {
+		if s.Err != nil {
+			sb.WriteString(fmt.Sprintf("  %d. %s init failed after %s: %s\n", s.Order+1, s.Name, s.InitDuration, s.Err))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %d. %s init %s\n", s.Order+1, s.Name, s.InitDuration))
+		}
+	}
+	if len(r.RunOrder) > 0 {
+		sb.WriteString(fmt.Sprintf("  run order: %s\n", strings.Join(r.RunOrder, ", ")))
+	}
+	if len(r.Skipped) > 0 {
+		sb.WriteString(fmt.Sprintf("  skipped: %s\n", strings.Join(r.Skipped, ", ")))
+	}
+	for _, w := range r.Warnings {
+		sb.WriteString(fmt.Sprintf("  warning: %s\n", w))
+	}
+	return sb.String()
+}
+
+// StartReport returns the report of the last StartAll call or nil if the container was not started yet
+func (c *Container) StartReport() *StartReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.startReport
+}
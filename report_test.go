@@ -0,0 +1,57 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartReport(t *testing.T) {
+	c := service.NewContainer(service.WithName("app"))
+	assert.Nil(t, c.StartReport())
+
+	s1 := &testService{Name: "s1"}
+	c.Register(s1)
+	s2 := &testService{Name: "s2", ErrorDuringInit: errors.New("init failed")}
+	c.Register(s2)
+	s3 := &testService{Name: "s3"}
+	c.Register(s3)
+
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	c.WaitAllStopped(context.Background())
+
+	report := c.StartReport()
+	require.NotNil(t, report)
+	assert.Equal(t, "app", report.Container)
+	assert.Equal(t, err, report.Err)
+	require.Len(t, report.Services, 2)
+	assert.Equal(t, s1.String(), report.Services[0].Name)
+	assert.NoError(t, report.Services[0].Err)
+	assert.Equal(t, s2.String(), report.Services[1].Name)
+	assert.Equal(t, 1, report.Services[1].Order)
+	assert.Error(t, report.Services[1].Err)
+	assert.Equal(t, []string{s3.String()}, report.Skipped)
+	assert.Contains(t, report.String(), "skipped: testService.s3")
+}
+
+func TestStartReport_runOrder(t *testing.T) {
+	c := service.NewContainer()
+	service.New("api").DependsOn("db").Run(blockUntilDone).Register(c)
+	service.New("db").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	report := c.StartReport()
+	assert.Equal(t, []string{"db", "api"}, report.RunOrder)
+	require.Len(t, report.Services, 2)
+	assert.False(t, report.Services[0].RunAt.IsZero())
+	assert.False(t, report.Services[1].RunAt.Before(report.Services[0].RunAt))
+	assert.Contains(t, report.String(), "run order: db, api")
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
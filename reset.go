@@ -0,0 +1,57 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// resetRun prepares a stopped container for the next StartAll.
+// Registered services are kept, instances of factories are removed, see RegisterFactory.
+// Panics when services of the previous run are still running.
+func (c *Container) resetRun() {
+	if state := c.State(); state != ContainerStopped && state != ContainerFailed {
+		panic("Container.StartAll can only be called again after all services stopped, container is " + state.String())
+	}
+	// The background shutdown of the previous run must be completed
+	<-c.shutdownDone
+
+	c.mu.Lock()
+	c.runCtx = nil
+	c.runCtxCancel = nil
+	c.runContexts = map[string]*runContext{}
+	c.carriedRestarts = map[string]int{}
+	c.callOnStopAllOnce = sync.Once{}
+	c.stopRequested.Store(false)
+	c.shuttingDown = false
+	c.flushed = false
+	c.startCount = 0
+	c.leakReport = nil
+	c.abortErr = nil
+	c.draining = false
+	c.attached = nil
+	c.serviceErrors = nil
+	if c.supervisor != nil {
+		c.supervisor.restarts = nil
+	}
+	services := c.services[:0:0]
+	for _, s := range c.services {
+		if s.family == "" {
+			services = append(services, s)
+		}
+	}
+	c.services = services
+	families := make([]*serviceFamily, 0, len(c.families))
+	for _, f := range c.families {
+		families = append(families, f)
+	}
+	c.mu.Unlock()
+
+	for _, f := range families {
+		f.mu.Lock()
+		f.keys = map[string]time.Time{}
+		f.evictorStarted = false
+		f.mu.Unlock()
+	}
+	c.notifyStatusChange()
+	c.log.Info("Reset container for next run", "container", c.name, "previous_run", c.RunID())
+}
@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartAll_again(t *testing.T) {
+	c := service.NewContainer()
+	var inits atomic.Int32
+	shutdowns := 0
+	c.OnShutdown(func() {
+		shutdowns++
+	})
+	service.New("worker").Init(func(ctx context.Context) error {
+		inits.Add(1)
+		return nil
+	}).Run(blockUntilDone).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	firstRun := c.RunID()
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.NotEqual(t, firstRun, c.RunID())
+	assert.Equal(t, 1, c.RunningCount())
+	assert.Equal(t, service.ContainerRunning, c.State())
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	assert.Equal(t, int32(2), inits.Load())
+	assert.Equal(t, 2, shutdowns)
+}
+
+func TestStartAll_whileRunningPanics(t *testing.T) {
+	c := service.NewContainer()
+	service.New("worker").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	assert.Panics(t, func() {
+		_ = c.StartAll(context.Background())
+	})
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestStartAll_againAfterServicesReturned(t *testing.T) {
+	c := service.NewContainer()
+	var runs atomic.Int32
+	service.New("job").Run(func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	require.NoError(t, c.WaitAllStopped(context.Background()))
+	assert.Equal(t, service.ContainerStopped, c.State())
+	assert.ErrorIs(t, c.ShutdownCause(), service.ErrAllServicesStopped)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	require.NoError(t, c.WaitAllStopped(context.Background()))
+	assert.Equal(t, int32(2), runs.Load())
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/pprof"
+	"time"
+)
+
+// WithResourceStats adds the number of goroutines per service to Status.
+// Goroutines are attributed by their pprof labels, see WithLeakCheck. Collecting the stats requires a goroutine
+// profile on every call of Status, thus it is disabled by default.
+func WithResourceStats() Option {
+	return func(c *Container) {
+		c.resourceStats = true
+	}
+}
+
+// SampleCPU records a CPU profile for the given duration and attributes the CPU time to services by their pprof labels.
+// The result is best-effort: only one CPU profile can be recorded per process at a time and CPU time of
+// goroutines without service labels is not reported. The result of the last sample is also part of Status.
+func (c *Container) SampleCPU(ctx context.Context, d time.Duration) (map[string]time.Duration, error) {
+	buf := &bytes.Buffer{}
+	err := pprof.StartCPUProfile(buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start cpu profile: %w", err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+	pprof.StopCPUProfile()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	samples, err := parseCPUProfile(buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cpu profile: %w", err)
+	}
+	runID := c.RunID()
+	usage := map[string]time.Duration{}
+	for _, s := range samples {
+		if s.labels[labelContainer] != c.name || s.labels[labelRun] != runID {
+			continue
+		}
+		usage[s.labels[labelService]] += s.cpu
+	}
+
+	c.mu.Lock()
+	c.cpuUsage = usage
+	c.mu.Unlock()
+	return usage, nil
+}
+
+type cpuSample struct {
+	cpu    time.Duration
+	labels map[string]string
+}
+
+// parseCPUProfile extracts the cpu time and string labels of all samples of a gzipped pprof protobuf profile.
+// Only the few fields needed are decoded, see https://github.com/google/pprof/blob/main/proto/profile.proto
+func parseCPUProfile(r io.Reader) ([]cpuSample, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+
+	type rawSample struct {
+		values []int64
+		labels [][2]int64
+	}
+	var sampleTypes []int64
+	var samples []rawSample
+	var stringTable []string
+
+	err = walkProto(data, func(field int, wireType int, value uint64, payload []byte) error {
+		switch field {
+		case 1: // sample_type
+			return walkProto(payload, func(field int, _ int, value uint64, _ []byte) error {
+				if field == 1 {
+					sampleTypes = append(sampleTypes, int64(value))
+				}
+				return nil
+			})
+		case 2: // sample
+			s := rawSample{}
+			err := walkProto(payload, func(field int, wireType int, value uint64, payload []byte) error {
+				switch field {
+				case 2: // value, packed or not
+					if wireType == 0 {
+						s.values = append(s.values, int64(value))
+						return nil
+					}
+					for len(payload) > 0 {
+						v, n := binary.Uvarint(payload)
+						if n <= 0 {
+							return errors.New("invalid packed value")
+						}
+						s.values = append(s.values, int64(v))
+						payload = payload[n:]
+					}
+				case 3: // label
+					label := [2]int64{}
+					err := walkProto(payload, func(field int, _ int, value uint64, _ []byte) error {
+						if field == 1 || field == 2 {
+							label[field-1] = int64(value)
+						}
+						return nil
+					})
+					if err != nil {
+						return err
+					}
+					s.labels = append(s.labels, label)
+				}
+				return nil
+			})
+			samples = append(samples, s)
+			return err
+		case 6: // string_table
+			stringTable = append(stringTable, string(payload))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	str := func(i int64) string {
+		if i < 0 || int(i) >= len(stringTable) {
+			return ""
+		}
+		return stringTable[i]
+	}
+	cpuIndex := -1
+	for i, t := range sampleTypes {
+		if str(t) == "cpu" {
+			cpuIndex = i
+		}
+	}
+	if cpuIndex < 0 {
+		return nil, errors.New("profile has no cpu sample type")
+	}
+
+	result := make([]cpuSample, 0, len(samples))
+	for _, s := range samples {
+		if cpuIndex >= len(s.values) {
+			continue
+		}
+		cs := cpuSample{cpu: time.Duration(s.values[cpuIndex]), labels: map[string]string{}}
+		for _, l := range s.labels {
+			cs.labels[str(l[0])] = str(l[1])
+		}
+		result = append(result, cs)
+	}
+	return result, nil
+}
+
+// walkProto calls f for every field of a protobuf message.
+// value is set for varint fields, payload for length delimited fields.
+func walkProto(data []byte, f func(field int, wireType int, value uint64, payload []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("invalid field key")
+		}
+		data = data[n:]
+		field, wireType := int(key>>3), int(key&7)
+		var value uint64
+		var payload []byte
+		switch wireType {
+		case 0:
+			value, n = binary.Uvarint(data)
+			if n <= 0 {
+				return errors.New("invalid varint")
+			}
+			data = data[n:]
+		case 1:
+			if len(data) < 8 {
+				return errors.New("invalid fixed64")
+			}
+			data = data[8:]
+		case 2:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				return errors.New("invalid length")
+			}
+			payload = data[n : n+int(length)]
+			data = data[n+int(length):]
+		case 5:
+			if len(data) < 4 {
+				return errors.New("invalid fixed32")
+			}
+			data = data[4:]
+		default:
+			return fmt.Errorf("unsupported wire type %d", wireType)
+		}
+		err := f(field, wireType, value, payload)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceStats(t *testing.T) {
+	c := service.NewContainer(service.WithResourceStats())
+	service.New("busy").Run(func(ctx context.Context) error {
+		for ctx.Err() == nil {
+			// Burn CPU
+		}
+		return nil
+	}).Register(c)
+	service.New("idle").Run(func(ctx context.Context) error {
+		for i := 0; i < 3; i++ {
+			go func() {
+				<-ctx.Done()
+			}()
+		}
+		<-ctx.Done()
+		return nil
+	}).Register(c)
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	defer c.WaitAllStopped(context.Background())
+	defer c.StopAll()
+
+	assert.Eventually(t, func() bool {
+		return c.Status()[1].Goroutines == 4
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, c.Status()[0].Goroutines)
+
+	usage, err := c.SampleCPU(context.Background(), 300*time.Millisecond)
+	require.NoError(t, err)
+	assert.Greater(t, usage["busy"], 50*time.Millisecond)
+	assert.Less(t, usage["idle"], 50*time.Millisecond)
+	assert.Equal(t, usage["busy"], c.Status()[0].CPU)
+}
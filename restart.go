@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// RestartPolicy defines when a service is restarted after it returned from Run, see WithRestartPolicy
+type RestartPolicy int
+
+const (
+	// RestartNever does not restart the service, this is the default
+	RestartNever RestartPolicy = iota
+	// RestartOnFailure restarts the service when Run returned an error
+	RestartOnFailure
+	// RestartAlways restarts the service whenever Run returned while the container is running
+	RestartAlways
+)
+
+func (p RestartPolicy) String() string {
+	switch p {
+	case RestartNever:
+		return "Never"
+	case RestartOnFailure:
+		return "OnFailure"
+	case RestartAlways:
+		return "Always"
+	default:
+		return "Unknown"
+	}
+}
+
+// Backoff configures the exponential delay between restarts
+type Backoff struct {
+	// Initial delay before the first restart, default is 100ms
+	Initial time.Duration
+	// Max delay between restarts, default is 30s
+	Max time.Duration
+	// Multiplier applied to the delay after each restart, default is 2
+	Multiplier float64
+	// MaxRestarts limits the consecutive restarts, or the retries of RetryUntilReady, 0 means unlimited.
+	// When the limit is reached the last error is handled as if there was no restart policy.
+	MaxRestarts int
+	// ResetAfter resets the delay and the restart count when Run did not return for the given duration,
+	// default is Max
+	ResetAfter time.Duration
+}
+
+// WithRestartPolicy restarts the service according to the policy instead of stopping the container
+// when Run returns. Without restart policy a failing service stops all services in the container.
+func WithRestartPolicy(policy RestartPolicy, backoff Backoff) RegisterOption {
+	return func(s *serviceInfo) {
+		s.restartPolicy = policy
+		s.backoff = backoff.withDefaults()
+	}
+}
+
+func (b Backoff) withDefaults() Backoff {
+	if b.Initial <= 0 {
+		b.Initial = 100 * time.Millisecond
+	}
+	if b.Max <= 0 {
+		b.Max = 30 * time.Second
+	}
+	if b.Multiplier < 1 {
+		b.Multiplier = 2
+	}
+	if b.ResetAfter <= 0 {
+		b.ResetAfter = b.Max
+	}
+	return b
+}
+
+// delay returns the delay before the given restart, starting with 0
+func (b Backoff) delay(restart int) time.Duration {
+	d := float64(b.Initial)
+	for i := 0; i < restart && d < float64(b.Max); i++ {
+		d *= b.Multiplier
+	}
+	return min(time.Duration(d), b.Max)
+}
+
+// shouldRestart decides if the service is restarted after Run returned with the given error
+func (s *serviceInfo) shouldRestart(err error) bool {
+	if errors.Is(err, ErrUnhealthy) {
+		return s.unhealthyThreshold > 0
+	}
+	switch s.restartPolicy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+// runWithRestarts calls run and restarts it according to the restart policy of the service
+// until it returns without restart or ctx is done. Returns the error of the last run.
+func (c *Container) runWithRestarts(ctx context.Context, rc *runContext, run func() error) error {
+	s := rc.service
+	logger := c.serviceLogger(s)
+	for {
+		started := time.Now()
+		err := run()
+		if ctx.Err() != nil || !s.shouldRestart(err) {
+			return err
+		}
+		backoff := s.backoff
+		if errors.Is(err, ErrUnhealthy) {
+			backoff = s.unhealthyBackoff
+		}
+		c.mu.Lock()
+		if time.Since(started) >= backoff.ResetAfter {
+			rc.consecutiveRestarts = 0
+			rc.extraRestarts = 0
+		}
+		restarts := rc.consecutiveRestarts
+		exceeded := backoff.MaxRestarts > 0 && restarts >= backoff.MaxRestarts+rc.extraRestarts
+		if !exceeded {
+			rc.consecutiveRestarts++
+			rc.restarts++
+		}
+		c.mu.Unlock()
+		if exceeded {
+			logger.Error("Service exceeded max restarts", "restarts", restarts, "error", err)
+			return fmt.Errorf("%w, exceeded %d restarts: %w", ErrCrashLoop, restarts, err)
+		}
+		delay := backoff.delay(restarts)
+		logger.Warn("Restarting service", "error", err, "delay", delay, "restart", restarts+1, "policy", s.restartPolicy)
+		c.notifyStatusChange()
+		c.emitEvent(EventRestarting, s.name, err)
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+// UnlimitedRestarts is the RestartBudget of services with a restart policy without MaxRestarts
+const UnlimitedRestarts = -1
+
+// RestartBudget returns how many consecutive restarts the restart policy of the service has left,
+// see Backoff.MaxRestarts and AddRestartBudget. Returns UnlimitedRestarts when MaxRestarts is not set
+// and 0 for services without restart policy. Returns an error if the service is not registered.
+func (c *Container) RestartBudget(name string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := slices.IndexFunc(c.services, func(s *serviceInfo) bool { return s.name == name })
+	if i < 0 {
+		return 0, fmt.Errorf("service '%s' not registered in container '%s'", name, c.name)
+	}
+	s := c.services[i]
+	if s.restartPolicy == RestartNever {
+		return 0, nil
+	}
+	if s.backoff.MaxRestarts == 0 {
+		return UnlimitedRestarts, nil
+	}
+	budget := s.backoff.MaxRestarts
+	if rc, ok := c.runContexts[name]; ok {
+		budget += rc.extraRestarts - rc.consecutiveRestarts
+	}
+	return max(budget, 0), nil
+}
+
+// AddRestartBudget grants the running service n more consecutive restarts, e.g. to nurse a flaky service
+// without redeploying. A negative n takes restarts away. The granted restarts expire with the restart count,
+// see Backoff.ResetAfter. A service that already exceeded its budget is not started again, see Restart.
+// Returns an error if the service is not started in the current run or its restart policy has no MaxRestarts.
+func (c *Container) AddRestartBudget(name string, n int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rc, ok := c.runContexts[name]
+	if !ok {
+		return fmt.Errorf("service '%s' not started in container '%s'", name, c.name)
+	}
+	if rc.service.restartPolicy == RestartNever || rc.service.backoff.MaxRestarts == 0 {
+		return fmt.Errorf("service '%s' has no restart budget", name)
+	}
+	rc.extraRestarts += n
+	c.log.Info("Changed restart budget", "name", name, "added", n, "container", c.name)
+	return nil
+}
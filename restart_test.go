@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestartOnFailure(t *testing.T) {
+	c := service.NewContainer()
+	var runs atomic.Int32
+	service.New("flaky").Run(func(ctx context.Context) error {
+		if runs.Add(1) < 3 {
+			return errors.New("flaky")
+		}
+		<-ctx.Done()
+		return nil
+	}).Restart(service.RestartOnFailure, service.Backoff{Initial: time.Millisecond}).Register(c)
+	service.New("main").Run(blockUntilDone).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	require.Eventually(t, func() bool { return runs.Load() == 3 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 2, c.RunningCount())
+	assert.Equal(t, 2, c.Status()[0].Restarts)
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	assert.Empty(t, c.ServiceErrors())
+}
+
+func TestRestartOnFailure_maxRestarts(t *testing.T) {
+	c := service.NewContainer()
+	runErr := errors.New("broken")
+	var runs atomic.Int32
+	service.New("broken").Run(func(ctx context.Context) error {
+		runs.Add(1)
+		return runErr
+	}).Restart(service.RestartOnFailure, service.Backoff{Initial: time.Millisecond, MaxRestarts: 2}).Register(c)
+	service.New("main").Run(blockUntilDone).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	c.WaitAllStopped(context.Background())
+	assert.Equal(t, int32(3), runs.Load())
+	assert.Len(t, c.ServiceErrors(), 1)
+}
+
+func TestAddRestartBudget(t *testing.T) {
+	c := service.NewContainer()
+	gate := make(chan struct{})
+	var runs atomic.Int32
+	service.New("flaky").Run(func(ctx context.Context) error {
+		switch runs.Add(1) {
+		case 3:
+			<-gate
+		case 5:
+			<-ctx.Done()
+			return nil
+		}
+		return errors.New("flaky")
+	}).Restart(service.RestartOnFailure, service.Backoff{Initial: time.Millisecond, MaxRestarts: 2}).Register(c)
+	service.New("main").Run(blockUntilDone).Register(c)
+
+	budget, err := c.RestartBudget("flaky")
+	require.NoError(t, err)
+	assert.Equal(t, 2, budget)
+	require.NoError(t, c.StartAll(context.Background()))
+	require.Eventually(t, func() bool { return runs.Load() == 3 }, time.Second, time.Millisecond)
+	budget, _ = c.RestartBudget("flaky")
+	assert.Equal(t, 0, budget)
+
+	require.NoError(t, c.AddRestartBudget("flaky", 2))
+	budget, _ = c.RestartBudget("flaky")
+	assert.Equal(t, 2, budget)
+	close(gate)
+	require.Eventually(t, func() bool { return runs.Load() == 5 }, time.Second, time.Millisecond)
+	assert.True(t, c.IsServiceRunning("flaky"))
+	assert.Error(t, c.AddRestartBudget("main", 1), "no restart policy")
+	budget, _ = c.RestartBudget("main")
+	assert.Equal(t, 0, budget)
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
+
+func TestRestartNever_isDefault(t *testing.T) {
+	c := service.NewContainer()
+	var runs atomic.Int32
+	service.New("once").Run(func(ctx context.Context) error {
+		runs.Add(1)
+		return errors.New("failed")
+	}).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	c.WaitAllStopped(context.Background())
+	assert.Equal(t, int32(1), runs.Load())
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// RetryUntilReady calls probe until it returns nil, waiting between the attempts according to the backoff.
+// It is meant to be used inside Init to wait for external dependencies, e.g. a database.
+// Failed attempts are logged with the logger of the service, see LoggerFromContext.
+// Backoff.MaxRestarts limits the retries, 0 retries until ctx is done.
+// Returns the last error of the probe when ctx is done or the retries are exhausted.
+func RetryUntilReady(ctx context.Context, backoff Backoff, probe func(ctx context.Context) error) error {
+	backoff = backoff.withDefaults()
+	logger := LoggerFromContext(ctx)
+	for attempt := 0; ; attempt++ {
+		err := probe(ctx)
+		if err == nil {
+			return nil
+		}
+		if backoff.MaxRestarts > 0 && attempt >= backoff.MaxRestarts {
+			return fmt.Errorf("not ready after %d attempts: %w", attempt+1, err)
+		}
+		delay := backoff.delay(attempt)
+		logger.Info("Not ready yet, retrying", "error", err, "attempt", attempt+1, "delay", delay)
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("not ready after %d attempts: %w (last error: %w)", attempt+1, ctx.Err(), err)
+		case <-t.C:
+		}
+	}
+}
+
+// DialWithBackoff connects to the address on the named network, see net.Dial.
+// Failed attempts are retried according to the backoff, see RetryUntilReady.
+func DialWithBackoff(ctx context.Context, backoff Backoff, network, address string) (net.Conn, error) {
+	var conn net.Conn
+	dialer := net.Dialer{}
+	err := RetryUntilReady(ctx, backoff, func(ctx context.Context) error {
+		var err error
+		conn, err = dialer.DialContext(ctx, network, address)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s %s: %w", network, address, err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,56 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryUntilReady(t *testing.T) {
+	attempts := 0
+	err := service.RetryUntilReady(context.Background(), service.Backoff{Initial: time.Millisecond}, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryUntilReady_contextDone(t *testing.T) {
+	probeErr := errors.New("unreachable")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := service.RetryUntilReady(ctx, service.Backoff{Initial: time.Millisecond}, func(ctx context.Context) error {
+		return probeErr
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, probeErr)
+}
+
+func TestDialWithBackoff(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	c := service.NewContainer()
+	service.New("client").Init(func(ctx context.Context) error {
+		conn, err := service.DialWithBackoff(ctx, service.Backoff{MaxRestarts: 3}, "tcp", l.Addr().String())
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
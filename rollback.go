@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WithStartupRollbackTimeout sets how long StartAll waits for already started services to stop
+// when the start fails, default is 5 seconds.
+func WithStartupRollbackTimeout(d time.Duration) Option {
+	return func(c *Container) {
+		c.rollbackTimeout = d
+	}
+}
+
+// rollback waits for all services of a failed start to stop and joins their errors with the start error
+func (c *Container) rollback(startErr error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.rollbackTimeout)
+	defer cancel()
+	errs := []error{startErr}
+	if !c.waitStopped(ctx) {
+		errs = append(errs, fmt.Errorf("services of container '%s' did not stop within %s after failed start", c.name, c.rollbackTimeout))
+	}
+	c.mu.Lock()
+	for _, rc := range c.orderedRunContexts() {
+		if rc.err != nil {
+			errs = append(errs, fmt.Errorf("service '%s' stopped with error: %w", rc.service.name, rc.err))
+		}
+	}
+	c.mu.Unlock()
+	if len(errs) == 1 {
+		return startErr
+	}
+	return errors.Join(errs...)
+}
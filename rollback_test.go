@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotReady = errors.New("not ready")
+
+// unreadyService runs but never becomes ready, see service.Readier
+type unreadyService struct {
+	run service.RunFunc
+}
+
+func (s *unreadyService) Run(ctx context.Context) error {
+	return s.run(ctx)
+}
+
+func (s *unreadyService) Ready(ctx context.Context) error {
+	return errNotReady
+}
+
+func (s *unreadyService) String() string {
+	return "db"
+}
+
+// registerFailingStart registers db with run and a dependent service, the start fails while db is running
+func registerFailingStart(c *service.Container, run service.RunFunc) {
+	c.Register(&unreadyService{run: run})
+	service.New("api").DependsOn("db").Run(blockUntilDone).Register(c)
+}
+
+func TestRollback_joinsErrors(t *testing.T) {
+	c := service.NewContainer()
+	dbErr := errors.New("db shutdown failed")
+	registerFailingStart(c, func(ctx context.Context) error {
+		<-ctx.Done()
+		return dbErr
+	})
+
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errNotReady)
+	assert.ErrorIs(t, err, dbErr)
+	assert.Contains(t, err.Error(), "service 'db' stopped with error")
+}
+
+func TestRollback_timeout(t *testing.T) {
+	c := service.NewContainer(service.WithStartupRollbackTimeout(20 * time.Millisecond))
+	release := make(chan struct{})
+	defer close(release)
+	registerFailingStart(c, func(ctx context.Context) error {
+		// Ignores the cancellation
+		<-release
+		return nil
+	})
+
+	start := time.Now()
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errNotReady)
+	assert.Contains(t, err.Error(), "did not stop within 20ms")
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestRollback_waitsForStoppedServices(t *testing.T) {
+	c := service.NewContainer()
+	stopped := atomic.Bool{}
+	registerFailingStart(c, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(30 * time.Millisecond)
+		stopped.Store(true)
+		return nil
+	})
+
+	err := c.StartAll(context.Background())
+	assert.ErrorIs(t, err, errNotReady)
+	assert.True(t, stopped.Load(), "StartAll must return after the rollback")
+	assert.False(t, c.IsServiceRunning("db"))
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+)
+
+// RunInfo identifies a single run of a container.
+// It is available in the context of all Init and Run calls, see RunInfoFromContext.
+type RunInfo struct {
+	// Container is the name of the container
+	Container string
+	// RunID is unique for every StartAll call
+	RunID string
+	// StartedAt is the time StartAll was called
+	StartedAt time.Time
+}
+
+type runInfoKey struct{}
+
+// RunInfoFromContext returns the RunInfo of the container the service is running in.
+// ok is false when the context does not belong to a container.
+func RunInfoFromContext(ctx context.Context) (info RunInfo, ok bool) {
+	info, ok = ctx.Value(runInfoKey{}).(RunInfo)
+	return info, ok
+}
+
+func withRunInfo(ctx context.Context, info RunInfo) context.Context {
+	return context.WithValue(ctx, runInfoKey{}, info)
+}
+
+type loggerKey struct{}
+
+// LoggerFromContext returns the logger of the service, it is available in the context of all Init and Run calls.
+// Returns slog.Default when the context does not belong to a service.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+func withLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+func newRunID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunInfoFromContext(t *testing.T) {
+	_, ok := service.RunInfoFromContext(context.Background())
+	assert.False(t, ok)
+
+	c := service.NewContainer(service.WithName("app"))
+	var initInfo, runInfo service.RunInfo
+	service.New("s1").
+		Init(func(ctx context.Context) error {
+			initInfo, _ = service.RunInfoFromContext(ctx)
+			return nil
+		}).
+		Run(func(ctx context.Context) error {
+			runInfo, _ = service.RunInfoFromContext(ctx)
+			<-ctx.Done()
+			return nil
+		}).Register(c)
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	assert.Equal(t, "app", initInfo.Container)
+	assert.NotEmpty(t, initInfo.RunID)
+	assert.False(t, initInfo.StartedAt.IsZero())
+	assert.Equal(t, initInfo, runInfo)
+}
+
+func TestRunID(t *testing.T) {
+	c := service.NewContainer()
+	assert.Empty(t, c.RunID())
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, c.RunID())
+	assert.Equal(t, c.RunID(), c.StartReport().RunID)
+
+	other := service.NewContainer()
+	err = other.StartAll(context.Background())
+	require.NoError(t, err)
+	assert.NotEqual(t, c.RunID(), other.RunID())
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RunOnce executes a short-lived task under supervision of the container and returns its result.
+// The task shares the operational behavior of services: it is logged like a service, panics are recovered
+// and returned as *PanicError and the task context is canceled when either ctx is done or the container stops.
+// Errors of the task are returned to the caller and do not stop the container.
+func RunOnce[T any](ctx context.Context, c *Container, name string, f func(ctx context.Context) (T, error)) (result T, err error) {
+	c.mu.Lock()
+	runCtx := c.runCtx
+	runInfo := c.runInfo
+	c.mu.Unlock()
+	if runCtx != nil {
+		ctx = withRunInfo(ctx, runInfo)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(runCtx, cancel)
+		defer stop()
+	}
+
+	logger := c.log.With("task", name, "container", c.name, "run", runInfo.RunID)
+	logger.Debug("Running task")
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task %s panicked: %w", name, newPanicError(r))
+		}
+		if err != nil {
+			logger.Error("Task failed", "error", err, "duration", time.Since(start))
+		} else {
+			logger.Debug("Task finished", "duration", time.Since(start))
+		}
+	}()
+
+	return f(ctx)
+}
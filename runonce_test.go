@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOnce(t *testing.T) {
+	c := service.NewContainer()
+
+	result, err := service.RunOnce(context.Background(), c, "answer", func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+
+	taskErr := errors.New("task failed")
+	_, err = service.RunOnce(context.Background(), c, "failing", func(ctx context.Context) (string, error) {
+		return "", taskErr
+	})
+	assert.ErrorIs(t, err, taskErr)
+}
+
+func TestRunOnce_panic(t *testing.T) {
+	c := service.NewContainer()
+
+	_, err := service.RunOnce(context.Background(), c, "panicking", func(ctx context.Context) (int, error) {
+		panic("boom")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	var panicErr *service.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+}
+
+func TestRunOnce_canceledWithContainer(t *testing.T) {
+	c := service.NewContainer()
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		c.StopAll()
+	}()
+
+	runID, err := service.RunOnce(context.Background(), c, "waiting", func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		info, _ := service.RunInfoFromContext(ctx)
+		return info.RunID, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, c.RunID(), runID)
+}
+
+func TestRunOnce_concurrentStartAll(t *testing.T) {
+	c := service.NewContainer()
+	service.New("s1").Run(blockUntilDone).Register(c)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			_, err := service.RunOnce(context.Background(), c, "task", func(ctx context.Context) (int, error) {
+				return 1, nil
+			})
+			assert.NoError(t, err)
+		}
+	}()
+	require.NoError(t, c.StartAll(context.Background()))
+	<-done
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
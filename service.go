@@ -12,8 +12,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"runtime"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -26,6 +29,10 @@ type genericService struct {
 	name string
 	init InitFunc
 	run  RunFunc
+	// health is the optional health check, see Builder.Health
+	health func(ctx context.Context) error
+	// opts are applied when the service is registered, see Builder
+	opts []RegisterOption
 }
 
 func (sr *genericService) Init(ctx context.Context) error {
@@ -38,6 +45,13 @@ func (sr *genericService) Run(ctx context.Context) error {
 	return sr.run(ctx)
 }
 
+func (sr *genericService) Health(ctx context.Context) error {
+	if sr.health == nil {
+		return nil
+	}
+	return sr.health(ctx)
+}
+
 func (sr *genericService) String() string {
 	return sr.name
 }
@@ -47,15 +61,73 @@ type runContext struct {
 	running bool
 	done    chan error
 	err     error
+	// initErr returned by Init, reported by Status
+	initErr error
+	// cancel stops only this service, see Container.stopInOrder
+	cancel context.CancelFunc
+	// abandoned services did not stop in time and are detached from the container, see Container.Abandon
+	abandoned bool
+	state     ServiceState
+	// seq is the position of the service in the start sequence of the container
+	seq int
+	// startedAt is the time Run was called, see WithAutoReadyDelay
+	startedAt time.Time
+	// readyAt is the time the service became ready for the first time, see WithStartupTimeout
+	readyAt time.Time
+	// restarts counts the restarts in the current run, see WithRestartPolicy
+	restarts int
+	// consecutiveRestarts of the restart policy and extraRestarts granted on top of the MaxRestarts,
+	// see Container.RestartBudget
+	consecutiveRestarts int
+	extraRestarts       int
+	// initDuration is the time Init took
+	initDuration time.Duration
+	// canceledAt is the time the context of the service was canceled, stoppedAt is the time Run returned
+	canceledAt time.Time
+	stoppedAt  time.Time
+	// stoppedByName is set when the service was stopped with Container.Stop or Container.Restart
+	// or an instance of a factory was stopped, e.g. with Container.StopInstance
+	stoppedByName bool
 }
 
 type serviceInfo struct {
-	name    string
-	service Runner
+	name             string
+	service          Runner
+	shutdownPriority int
+	// family is the prefix of the factory that created the service, see RegisterFactory
+	family string
+	// initBefore and initAfter contain names of init barriers, see InitBarrierUser
+	initBefore []string
+	initAfter  []string
+	// phase, tags and dependsOn, see Phaser, Tagger and Dependent
+	phase     int
+	tags      []string
+	dependsOn []string
+	// critical services stop the container when Init or Run fails, see CriticalityReporter
+	critical bool
+	// stopTimeout limits the time the shutdown waits for the service, see WithStopTimeout
+	stopTimeout time.Duration
+	// restartPolicy and backoff, see WithRestartPolicy
+	restartPolicy RestartPolicy
+	backoff       Backoff
+	// recoverPanics in Init and Run, see WithPanicRecovery
+	recoverPanics bool
+	// lockOSThread locks Run to an OS thread, see WithLockedOSThread
+	lockOSThread bool
+	// afterStop hooks are called when Run returned, see WithAfterStop
+	afterStop []func()
+	// unhealthyThreshold and unhealthyBackoff, see WithRestartOnUnhealthy
+	unhealthyThreshold int
+	unhealthyBackoff   Backoff
+	// startupTimeout limits the time to become ready, see WithStartupTimeout
+	startupTimeout time.Duration
 }
 
-func (rc *runContext) wait() {
-	if !rc.running {
+func (rc *runContext) wait(mu *sync.Mutex) {
+	mu.Lock()
+	running := rc.running
+	mu.Unlock()
+	if !running {
 		return
 	}
 	<-rc.done
@@ -73,12 +145,121 @@ type Container struct {
 	// Context in which all services are running
 	runCtx context.Context
 	// Cancel method of the runCtx, when called all services should stop
-	runCtxCancel      context.CancelFunc
-	services          []*serviceInfo
-	runContexts       map[string]*runContext
+	runCtxCancel context.CancelCauseFunc
+	services     []*serviceInfo
+	runContexts  map[string]*runContext
+	// carriedRestarts are the restart counts of services restarted by name, taken over by their next runContext
+	carriedRestarts   map[string]int
 	log               *slog.Logger
 	callOnStopAllOnce sync.Once
-	shutdownCallbacks []func()
+	// stopRequested is set by the first call of StopAll
+	stopRequested     atomic.Bool
+	shutdownCallbacks []shutdownCallback
+	// callbackWarnAfter is the duration after which a slow shutdown callback is logged
+	callbackWarnAfter time.Duration
+	// mu guards the runContexts, shutdownCallbacks and shuttingDown
+	mu sync.Mutex
+	// shuttingDown is set as soon as the runCtx is canceled, services started afterwards are stopped immediately
+	shuttingDown bool
+	// opts the container was created with, used to create a Blueprint
+	opts []Option
+	// startReport of the last StartAll call
+	startReport *StartReport
+	// runInfo of the current run, injected into the runCtx
+	runInfo RunInfo
+	// statusWatchers are notified on every state change of a service, see WatchStatus
+	statusWatchers map[chan struct{}]struct{}
+	// families of dynamically created services by prefix, see RegisterFactory
+	families map[string]*serviceFamily
+	// barriers are the names of all init barriers, see WithInitBarrier
+	barriers []string
+	// strictRegistration enables verifyService on Register
+	strictRegistration bool
+	// registerWarnings collected by verifyService, reported in the StartReport
+	registerWarnings []string
+	// requiredCapabilities are verified by StartAll, see WithRequiredCapabilities
+	requiredCapabilities []Capability
+	flushers             []flusher
+	flushTimeout         time.Duration
+	// disabledServices and disabledServicesEnv, see WithDisabledServices and WithDisabledServicesEnv
+	disabledServices    []string
+	disabledServicesEnv string
+	// profiles of the current run, see WithProfiles
+	profiles []string
+	// excluded are the registered services not started in the current run, they are not part of the Health
+	excluded map[string]bool
+	// statsLogInterval in which the Stats are logged, see WithStatsLogInterval
+	statsLogInterval time.Duration
+	// errorSink persists service errors, see WithErrorSink
+	errorSink ErrorSink
+	// alerter is called for significant conditions, see WithAlerter
+	alerter func(a Alert)
+	// closers are closed after all services stopped, see ManageCloser
+	closers []managedCloser
+	// flushed is set after the flushers were executed in the current run
+	flushed bool
+	// reportOrder of services in ServiceNames, Status and log output
+	reportOrder ReportOrder
+	// startCount is the number of services started in the current run, used for runContext.seq
+	startCount int
+	leakCheck  bool
+	// goroutinesBefore is the number of goroutines before StartAll, see WithLeakCheck
+	goroutinesBefore int
+	leakReport       *LeakReport
+	resourceStats    bool
+	// cpuUsage per service of the last SampleCPU call
+	cpuUsage map[string]time.Duration
+	// starting is true while StartAll is executed
+	starting bool
+	// abortErr is the reason passed to AbortStart
+	abortErr error
+	// rollbackTimeout limits the time StartAll waits for started services to stop after a failed start
+	rollbackTimeout time.Duration
+	// parentCancelDelay delays stopping the services after the parent context is canceled
+	parentCancelDelay time.Duration
+	// draining is set when the parent context was canceled and services are about to stop
+	draining bool
+	// parentCanceledCallbacks are called when the parent context is canceled
+	parentCanceledCallbacks []func(cause error)
+	// autoReadyDelay after which services without readiness interface are ready
+	autoReadyDelay time.Duration
+	// reverseShutdown stops services one by one in reverse start order, see WithReverseOrderShutdown
+	reverseShutdown bool
+	// serviceDefaults are applied to every registered service, see WithServiceDefaults
+	serviceDefaults []RegisterOption
+	// stateMu guards the fields for the OnStateChange callbacks, it is never held while calling them
+	stateMu           sync.Mutex
+	stateCallbacks    []func(from, to ContainerState)
+	lastState         ContainerState
+	pendingStates     []stateTransition
+	dispatchingStates bool
+	// shutdownReport of the current or last run, see LastShutdownReport
+	shutdownReport     *ShutdownReport
+	shutdownReportFile string
+	// tracer creates spans for the lifecycle, see WithTracer
+	tracer Tracer
+	// eventMu guards the eventSubscribers, see Subscribe
+	eventMu          sync.Mutex
+	eventSubscribers map[*eventSubscriber]struct{}
+	// shutdownDone is closed when the background shutdown of the current run completed
+	shutdownDone chan struct{}
+	// nameSeparator between the names of merged containers and their services, see WithNameSeparator
+	nameSeparator string
+	// supervisor restarts failed services, nil stops the container on failure, see WithSupervisor
+	supervisor *supervisor
+	// recoverPanics of all services, see WithRecoverPanics
+	recoverPanics bool
+	// healthCheckInterval of services restarted when unhealthy, see WithHealthCheckInterval
+	healthCheckInterval time.Duration
+	// attached tasks of the current run, see GoAttached
+	attached []*attachedTask
+	// serviceErrors of the current run, see Errors
+	serviceErrors []ServiceError
+	// abandonCallbacks are called by Abandon
+	abandonCallbacks []func(names []string)
+	// runMiddlewares and initMiddlewares wrap every service, see Use and UseInit
+	runMiddlewares  []RunMiddleware
+	initMiddlewares []InitMiddleware
 }
 
 type Option func(c *Container)
@@ -87,9 +268,21 @@ func NewContainer(opts ...Option) *Container {
 
 	nopLogger := slog.New(NopHandler{})
 	c := &Container{
-		services:    make([]*serviceInfo, 0),
-		runContexts: map[string]*runContext{},
-		log:         nopLogger,
+		services:        make([]*serviceInfo, 0),
+		runContexts:     map[string]*runContext{},
+		carriedRestarts: map[string]int{},
+		log:             nopLogger,
+		opts:            opts,
+
+		callbackWarnAfter: 5 * time.Second,
+		flushTimeout:      5 * time.Second,
+		rollbackTimeout:   5 * time.Second,
+		statusWatchers:    map[chan struct{}]struct{}{},
+		eventSubscribers:  map[*eventSubscriber]struct{}{},
+		families:          map[string]*serviceFamily{},
+		nameSeparator:     defaultNameSeparator,
+
+		healthCheckInterval: 10 * time.Second,
 	}
 	for _, o := range opts {
 		o(c)
@@ -117,28 +310,131 @@ func (c *Container) Name() string {
 	return c.name
 }
 
+// RunID returns the unique ID of the current run, it changes with every call of StartAll.
+// Returns an empty string before the container was started.
+func (c *Container) RunID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.runInfo.RunID
+}
+
 func (c *Container) SetLogger(logger *slog.Logger) {
 	c.log = logger
 }
 
-// Register adds a service to the list of services to be initialized
-func (c *Container) Register(service Runner) {
+// Register adds a service to the list of services to be initialized.
+// The options configure the service in addition to the optional interfaces it implements.
+// When the container is already running, the service is started right away, see StartOne,
+// unless it is not part of the profiles the container was started with or disabled, see WithProfiles and
+// WithDisabledServices. Errors of the start are logged, use StartOne to handle them.
+// When the container is stopping or stopped, the service is started with the next call of StartAll.
+func (c *Container) Register(service Runner, opts ...RegisterOption) {
+	info := newServiceInfo(service, c.serviceDefaults...)
+	for _, opt := range opts {
+		opt(info)
+	}
+	err := c.addService(info)
+	if err != nil {
+		panic(err.Error())
+	}
+	c.mu.Lock()
+	profiles := c.profiles
+	running := c.runCtx != nil && c.runCtx.Err() == nil && !c.shuttingDown
+	c.mu.Unlock()
+	if !running {
+		return
+	}
+	if !inProfiles(info, profiles) || slices.Contains(c.disabledNames(), info.name) {
+		c.exclude(info.name)
+		return
+	}
+	if err := c.startOne(context.Background(), info); err != nil {
+		c.serviceLogger(info).Error("Failed to start service registered after StartAll", "error", err)
+	}
+}
+
+// newServiceInfo applies the defaults, the optional interfaces of the service and the options of the Builder in that order
+func newServiceInfo(service Runner, defaults ...RegisterOption) *serviceInfo {
 	name := fmt.Sprintf("%T", service)
 	if s, ok := service.(fmt.Stringer); ok {
 		name = s.String()
 	}
 
+	info := &serviceInfo{
+		name:     name,
+		service:  service,
+		critical: true,
+	}
+	for _, opt := range defaults {
+		opt(info)
+	}
+	if w, ok := service.(interface{ primary() Runner }); ok {
+		// Optional interfaces are implemented by the wrapped instance, see Standby
+		service = w.primary()
+	}
+	if p, ok := service.(ShutdownPrioritizer); ok {
+		info.shutdownPriority = p.ShutdownPriority()
+	}
+	if b, ok := service.(InitBarrierUser); ok {
+		info.initBefore = b.InitBefore()
+		info.initAfter = b.InitAfter()
+	}
+	if p, ok := service.(Phaser); ok {
+		info.phase = p.Phase()
+	}
+	if t, ok := service.(Tagger); ok {
+		info.tags = t.Tags()
+	}
+	if d, ok := service.(Dependent); ok {
+		info.dependsOn = d.DependsOn()
+	}
+	if cr, ok := service.(CriticalityReporter); ok {
+		info.critical = cr.Critical()
+	}
+	if g, ok := service.(*genericService); ok {
+		for _, opt := range g.opts {
+			opt(info)
+		}
+	}
+	return info
+}
+
+// addService appends the service to the list of services, names must be unique
+func (c *Container) addService(info *serviceInfo) error {
+	c.mu.Lock()
 	for _, s := range c.services {
-		if s.name == name {
-			panic(fmt.Sprintf("Service '%s' already registered in container %s", name, c.name))
+		if s.name == info.name {
+			c.mu.Unlock()
+			return fmt.Errorf("Service '%s' already registered in container %s", info.name, c.name)
 		}
 	}
+	c.services = append(c.services, info)
+	var warnings []string
+	if c.strictRegistration {
+		warnings = verifyService(info)
+		c.registerWarnings = append(c.registerWarnings, warnings...)
+	}
+	c.mu.Unlock()
 
-	c.services = append(c.services, &serviceInfo{
-		name:    name,
-		service: service,
-	})
-	c.log.Info("Registered service", "name", name, "container", c.name)
+	for _, w := range warnings {
+		c.log.Warn("Suspicious service registration", "warning", w, "name", info.name, "container", c.name)
+	}
+	c.log.Info("Registered service", "name", info.name, "container", c.name)
+	c.emitEvent(EventRegistered, info.name, nil)
+	c.notifyStatusChange()
+	return nil
+}
+
+// registeredServices returns a copy of the list of registered services
+func (c *Container) registeredServices() []*serviceInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*serviceInfo{}, c.services...)
+}
+
+// serviceLogger returns the logger used for all log output regarding the given service
+func (c *Container) serviceLogger(s *serviceInfo) *slog.Logger {
+	return c.log.With("name", s.name, "container", c.name, "run", c.RunID())
 }
 
 func newRunContext(s *serviceInfo) *runContext {
@@ -149,21 +445,43 @@ func newRunContext(s *serviceInfo) *runContext {
 }
 
 func (c *Container) initOne(ctx context.Context, s *serviceInfo) error {
-	c.onInit(s)
 	runner := newRunContext(s)
+	c.mu.Lock()
 	if _, ok := c.runContexts[s.name]; ok {
+		c.mu.Unlock()
 		return fmt.Errorf("service '%s' already started in container '%s'", s.name, c.name)
 	}
 
+	runner.seq = c.startCount
+	c.startCount++
+	runner.restarts = c.carriedRestarts[s.name]
+	delete(c.carriedRestarts, s.name)
 	c.runContexts[s.name] = runner
+	c.mu.Unlock()
+	c.setState(runner, StateInitializing)
 
-	logger := c.log.With("name", s.name)
-	logger = logger.With("container", c.name)
+	logger := c.serviceLogger(s)
 
 	// Execute initialization code if any
 	if initer, ok := s.service.(Initer); ok {
 		logger.Info("Initializing service")
-		err := initer.Init(ctx)
+		var err error
+		initStart := time.Now()
+		c.withServiceContext(ctx, s, func(ctx context.Context) {
+			ctx, endSpan := c.startSpan(ctx, SpanInit, s)
+			err = c.callService(s, func() error {
+				return c.chainInit(initer.Init)(ctx)
+			})
+			endSpan(err)
+		})
+		c.mu.Lock()
+		runner.initDuration = time.Since(initStart)
+		runner.initErr = err
+		if _, ok := err.(*PanicError); ok {
+			// Recovered panics are reported like errors of Run, see WithPanicRecovery
+			runner.err = err
+		}
+		c.mu.Unlock()
 		if err != nil {
 			go func() {
 				// Let the runner stop immediately
@@ -171,17 +489,20 @@ func (c *Container) initOne(ctx context.Context, s *serviceInfo) error {
 				runner.done <- nil
 			}()
 			logger.Debug("Failed to initialize service", "error", err)
+			c.recordError(s, LifecycleInit, err)
+			c.setStateErr(runner, StateFailed, err)
 			return fmt.Errorf("failed to init service %s: %w", s.name, err)
 		}
-		logger.Info("Initialized service")
+		logger.Info("Initialized service", "duration", time.Since(initStart))
 	}
 
 	return nil
 }
 
 func (c *Container) runOne(ctx context.Context, s *serviceInfo) error {
-	c.onRun(s)
+	c.mu.Lock()
 	runner, ok := c.runContexts[s.name]
+	c.mu.Unlock()
 	if !ok {
 		return fmt.Errorf("service '%s' not initialized in container '%s'", s.name, c.name)
 	}
@@ -189,23 +510,97 @@ func (c *Container) runOne(ctx context.Context, s *serviceInfo) error {
 		return fmt.Errorf("service '%s' already running in container '%s'", s.name, c.name)
 	}
 
+	// Each service gets its own context, so services can be stopped in order of their shutdown priority.
+	// Values of the run context are still visible to the service.
+	svcCtx, cancelCause := context.WithCancelCause(context.WithoutCancel(ctx))
+	cancel := func() {
+		cancelCause(c.ShutdownCause())
+	}
+
 	// Execute the actual run method in background
+	c.mu.Lock()
 	runner.running = true
+	runner.cancel = func() {
+		c.mu.Lock()
+		if runner.canceledAt.IsZero() {
+			runner.canceledAt = time.Now()
+		}
+		stopping := runner.running && runner.state == StateRunning
+		if stopping {
+			runner.state = StateStopping
+		}
+		c.mu.Unlock()
+		if stopping {
+			c.notifyStatusChange()
+			c.emitEvent(EventStopping, s.name, nil)
+		}
+		cancel()
+	}
+	runner.startedAt = time.Now()
+	if c.shuttingDown {
+		// The container is already stopping, the service must return right away
+		cancelCause(context.Cause(c.runCtx))
+	}
+	c.mu.Unlock()
+	c.setState(runner, StateRunning)
 	go func() {
-		logger := c.log.With("name", s.name)
-		logger = logger.With("container", c.name)
+		if s.lockOSThread {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+		}
+		defer cancel()
+		logger := c.serviceLogger(s)
 		logger.Info("Starting service")
-		runErr := s.service.Run(ctx)
-		if runErr != nil {
-			logger.Error("Service stopped with error", "error", runErr)
-		} else {
-			logger.Info("Service stopped")
+		var runErr error
+		c.withServiceContext(svcCtx, s, func(ctx context.Context) {
+			ctx, endSpan := c.startSpan(ctx, SpanRun, s)
+			runErr = c.runWithRestarts(ctx, runner, func() error {
+				return c.runWatched(ctx, runner, func(ctx context.Context) error {
+					return c.callService(s, func() error {
+						return c.chainRun(s.service.Run)(ctx)
+					})
+				})
+			})
+			endSpan(runErr)
+		})
+		c.mu.Lock()
+		abandoned := runner.abandoned
+		c.mu.Unlock()
+		if abandoned {
+			logger.Warn("Abandoned service returned", "error", runErr)
+			return
 		}
+		c.mu.Lock()
 		runner.err = runErr
 		runner.running = false
-		close(runner.done)
+		runner.stoppedAt = time.Now()
+		runDuration := runner.stoppedAt.Sub(runner.startedAt)
+		c.mu.Unlock()
 		if runErr != nil {
-			c.StopAll()
+			logger.Error("Service stopped with error", "error", runErr, "run_duration", runDuration)
+			c.recordError(s, LifecycleRun, runErr)
+		} else {
+			logger.Info("Service stopped", "run_duration", runDuration)
+		}
+		if runErr != nil {
+			c.setState(runner, StateFailed)
+		} else {
+			c.setState(runner, StateStopped)
+		}
+		c.runAfterStop(s)
+		c.mu.Lock()
+		stoppedByName := runner.stoppedByName
+		c.mu.Unlock()
+		close(runner.done)
+		c.updateState()
+		if runErr != nil && stoppedByName {
+			logger.Warn("Service stopped by name failed, keep other services running")
+		} else if runErr != nil && c.supervisor != nil {
+			c.supervise(runner, runErr)
+		} else if runErr != nil && !s.critical {
+			logger.Warn("Non-critical service failed, keep other services running")
+		} else if runErr != nil {
+			c.stopAll(fmt.Errorf("service '%s' failed: %w", s.name, runErr))
 		}
 	}()
 
@@ -213,81 +608,246 @@ func (c *Container) runOne(ctx context.Context, s *serviceInfo) error {
 }
 
 // StartAll starts all services inside the container
-// the function does not block, services are started in background
-func (c *Container) StartAll(ctx context.Context) error {
-	if c.runCtx != nil {
-		panic("Container.StartAll can only be called once")
+// the function does not block, services are started in background.
+// Only services with dependencies are run after the services they depend on are ready, see Dependent.
+// After all services stopped, StartAll can be called again to init and run all registered services in a new run.
+// Instances of factories are not restarted. Panics when called while services are still running.
+// A subset of the services can be started with WithProfiles.
+func (c *Container) StartAll(ctx context.Context, opts ...StartOption) error {
+	if c.IsRunning() {
+		c.resetRun()
+	}
+	startOpts := &startOptions{}
+	for _, o := range opts {
+		o(startOpts)
+	}
+	if c.leakCheck {
+		c.goroutinesBefore = runtime.NumGoroutine()
+	}
+	c.mu.Lock()
+	c.runInfo = RunInfo{
+		Container: c.name,
+		RunID:     newRunID(),
+		StartedAt: time.Now(),
+	}
+	c.profiles = startOpts.profiles
+	c.excluded = map[string]bool{}
+	c.mu.Unlock()
+	parentCtx := ctx
+	ctx, endSpan := c.startSpan(ctx, SpanStartAll, nil)
+
+	c.mu.Lock()
+	// The run context is detached from the parent, cancellation of the parent is propagated by onParentCanceled
+	c.runCtx, c.runCtxCancel = context.WithCancelCause(context.WithoutCancel(withRunInfo(ctx, c.runInfo)))
+	c.draining = false
+	c.starting = true
+	report := &StartReport{
+		Container: c.name,
+		RunID:     c.runInfo.RunID,
+		StartedAt: c.runInfo.StartedAt,
+		Warnings:  append([]string{}, c.registerWarnings...),
+	}
+	c.mu.Unlock()
+	if c.statsLogInterval > 0 {
+		go c.logStats(c.runCtx)
+	}
+	stopParentWatch := context.AfterFunc(parentCtx, func() {
+		c.onParentCanceled(context.Cause(parentCtx))
+	})
+	c.updateState()
+	shutdownDone := make(chan struct{})
+	c.shutdownDone = shutdownDone
+	go func() {
+		defer close(shutdownDone)
+		<-c.runCtx.Done()
+		stopParentWatch()
+		c.beginShutdown("run context canceled")
+		c.recordShutdown(func(r *ShutdownReport) {
+			r.CanceledAt = time.Now()
+		})
+		c.updateState()
+		c.stopInOrder()
+		c.finishShutdown()
+		c.updateState()
+	}()
+
+	defer func() {
+		report.Duration = time.Since(report.StartedAt)
+		c.mu.Lock()
+		c.startReport = report
+		c.starting = false
+		c.mu.Unlock()
+		endSpan(report.Err)
+		c.updateState()
+	}()
+
+	// fail stops all services that were already started
+	fail := func(err error) error {
+		if abortErr := c.startAborted(); abortErr != nil {
+			err = abortErr
+		}
+		c.markNotStarted()
+		c.stopAll(fmt.Errorf("startup failed: %w", err))
+		err = c.rollback(err)
+		report.Err = err
+		return err
+	}
+
+	registered := c.registeredServices()
+	selected := selectProfiles(registered, startOpts.profiles)
+	for _, s := range registered {
+		if !slices.Contains(selected, s) {
+			c.exclude(s.name)
+			report.Skipped = append(report.Skipped, s.name)
+		}
+	}
+	services, skipped := skipDisabled(selected, c.disabledNames())
+	skippedNames := make([]string, 0, len(skipped))
+	for name := range skipped {
+		skippedNames = append(skippedNames, name)
+	}
+	slices.Sort(skippedNames)
+	for _, name := range skippedNames {
+		c.log.Warn("Service disabled, not starting", "name", name, "reason", skipped[name], "container", c.name)
+		report.Warnings = append(report.Warnings, fmt.Sprintf("service '%s' not started: %s", name, skipped[name]))
+		report.Skipped = append(report.Skipped, name)
+		c.exclude(name)
+	}
+	services, err := c.startOrder(services)
+	if err != nil {
+		return fail(err)
+	}
+	if err := c.verifyCapabilities(services); err != nil {
+		return fail(err)
 	}
-	c.runCtx, c.runCtxCancel = context.WithCancel(ctx)
 
 	// Iterate over all services to initialize them
-	for i := range c.services {
-		s := c.services[i]
+	failedInit := map[string]bool{}
+	for i := range services {
+		s := services[i]
+		if err := c.startAborted(); err != nil {
+			for _, skipped := range services[i:] {
+				report.Skipped = append(report.Skipped, skipped.name)
+			}
+			return fail(err)
+		}
 		// TODO: Should we allow services to optionally initialize in parallel? Then we might get multiple errors returned
-		err := c.initOne(c.runCtx, s)
+		initStart := time.Now()
+		err = c.initOne(c.runCtx, s)
+		report.Services = append(report.Services, ServiceStartInfo{
+			Name:         s.name,
+			Order:        i,
+			InitDuration: time.Since(initStart),
+			Err:          err,
+		})
+		if err != nil && !s.critical {
+			c.serviceLogger(s).Warn("Non-critical service failed to initialize, start other services", "error", err)
+			report.Warnings = append(report.Warnings, fmt.Sprintf("non-critical service '%s' not started: %v", s.name, err))
+			failedInit[s.name] = true
+			continue
+		}
 		if err != nil {
-			c.StopAll()
-			return err
+			for _, skipped := range services[i+1:] {
+				report.Skipped = append(report.Skipped, skipped.name)
+			}
+			return fail(err)
 		}
 	}
 
 	// Iterate over all services to run them
-	for i := range c.services {
-		s := c.services[i]
-		err := c.runOne(c.runCtx, s)
+	// Services are only run when the services they depend on are ready
+	for i := range services {
+		s := services[i]
+		if failedInit[s.name] {
+			continue
+		}
+		waitStart := time.Now()
+		if err := c.waitDependencies(c.runCtx, s); err != nil {
+			return fail(err)
+		}
+		report.Services[i].DependencyWait = time.Since(waitStart)
+		report.Services[i].RunAt = time.Now()
+		err = c.runOne(c.runCtx, s)
 		if err != nil {
-			c.StopAll()
-			return err
+			return fail(err)
 		}
+		report.RunOrder = append(report.RunOrder, s.name)
+	}
+	if err := c.startAborted(); err != nil {
+		return fail(err)
 	}
 
 	return nil
 }
 
-// WaitAllRunningTimeout blocks until all services are running or the context is canceled
-// NOTE: We want to introduce a version with context instead of the duration. But that needs some refactoring in current client
-// thus this will be deprecated in future and has the "Timeout" stated in the name
+// WaitAllRunningTimeout blocks until all services are running and ready or the timeout is reached.
+// Returns true if all services are ready.
+//
+// Deprecated: Use WaitAllReady, this is a wrapper to support legacy code.
 func (c *Container) WaitAllRunningTimeout(timeout time.Duration) bool {
-	wg := sync.WaitGroup{}
-	allReady := atomic.Bool{}
-	// Assume all are ready, until one is not
-	allReady.Store(true)
-	for _, runCtx := range c.runningServices() {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if waiter, ok := runCtx.service.service.(ReadyWaiter); ok {
-				ready := waiter.WaitReady(timeout)
-				if !ready {
-					allReady.Store(false)
-				}
-			}
-		}()
-	}
-	wg.Wait()
-	return allReady.Load()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return c.WaitAllReady(ctx) == nil
 }
 
+// IsRunning returns true once StartAll was called, see State for details
 func (c *Container) IsRunning() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.runCtx != nil
 }
 
+// Context returns the run context of the container, it is canceled when the container stops.
+// Use it to derive contexts for goroutines outside of services, it carries the RunInfo like the context of services.
+// Panics when called before StartAll.
+func (c *Container) Context() context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.runCtx == nil {
+		panic("call Container.StartAll() before Context()")
+	}
+	return c.runCtx
+}
+
 // StopAll gracefully stops all services.
 // If you need a timeout, passe a context with Timeout or Deadline
+// StopAll is safe to be called concurrently, only the first call executes the shutdown callbacks and
+// cancels the services, all further calls return immediately. See State to check if the stop is in progress.
 func (c *Container) StopAll() {
+	c.stopAll(ErrStopAll)
+}
+
+// StopAllAndWait stops all services like StopAll and waits until they stopped like WaitAllStopped.
+// Returns the joined errors of all services and the ctx error when ctx is done before all services stopped.
+func (c *Container) StopAllAndWait(ctx context.Context) error {
+	c.StopAll()
+	return c.WaitAllStopped(ctx)
+}
+
+// stopAll stops all services like StopAll, the cause is reported by ShutdownCause if the shutdown was not requested before
+func (c *Container) stopAll(cause error) {
+	c.mu.Lock()
+	cancel := c.runCtxCancel
+	c.mu.Unlock()
+	if cancel == nil {
+		panic("call Container.StartAll() before StopAll()")
+	}
+	if c.stopRequested.Swap(true) {
+		return
+	}
+	c.beginShutdown(cause.Error())
+	c.updateState()
 	c.callOnStopAllOnce.Do(func() {
 		c.onStopAll()
 	})
-	if c.runCtxCancel == nil {
-		panic("call Container.StartAll() before StopAll()")
-	}
-	c.runCtxCancel()
+	cancel(cause)
 }
 
 func (c *Container) runningServices() []*runContext {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	rcs := make([]*runContext, 0)
-	for i := range c.runContexts {
-		rc := c.runContexts[i]
+	for _, rc := range c.orderedRunContexts() {
 		if rc.running {
 			rcs = append(rcs, rc)
 		}
@@ -296,6 +856,8 @@ func (c *Container) runningServices() []*runContext {
 }
 
 func (c *Container) RunningCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	cnt := 0
 	for _, rc := range c.runContexts {
 		if rc.running {
@@ -305,10 +867,13 @@ func (c *Container) RunningCount() int {
 	return cnt
 }
 
+// ServiceNames returns the names of all started services in the order defined by WithReportOrder
 func (c *Container) ServiceNames() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	var names []string
 
-	for _, rc := range c.runContexts {
+	for _, rc := range c.orderedRunContexts() {
 		names = append(names, rc.service.name)
 	}
 
@@ -317,18 +882,67 @@ func (c *Container) ServiceNames() []string {
 
 // WaitAllStopped blocks until all services are stopped or context is canceled.
 // After the context is canceled, services might still run. Call Container.StopAll() to stop them.
-func (c *Container) WaitAllStopped(ctx context.Context) {
-	if c.runCtxCancel == nil {
+// When the container is shutting down, services that are still running are logged, see StuckServices.
+// When all services stopped, the flushers are executed and the managed resources are closed before
+// WaitAllStopped returns, see RegisterFlusher and ManageCloser. When all services returned from Run on their own,
+// the run ends with ErrAllServicesStopped and the container can be started again.
+// Returns the joined errors of all services, see Errors, and the ctx error when ctx is done before all services stopped.
+func (c *Container) WaitAllStopped(ctx context.Context) error {
+	c.mu.Lock()
+	started := c.runCtxCancel != nil
+	c.mu.Unlock()
+	if !started {
 		panic("call Container.StartAll() before WaitAllStopped()")
 	}
 
+	var errs []error
+	if c.waitStopped(ctx) {
+		c.endRun()
+		c.flush()
+		c.closeResources()
+		c.checkLeaks()
+	} else {
+		c.logStuckServices()
+		errs = append(errs, fmt.Errorf("waiting for services in container '%s' to stop: %w", c.name, ctx.Err()))
+	}
+	for _, err := range c.Errors() {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// endRun ends a run in which all services returned from Run without a shutdown being requested,
+// so the container reports ContainerStopped and can be started again
+func (c *Container) endRun() {
+	c.mu.Lock()
+	ended := c.starting || c.runCtx.Err() != nil
+	shutdownDone := c.shutdownDone
+	c.mu.Unlock()
+	if ended {
+		return
+	}
+	c.stopAll(ErrAllServicesStopped)
+	<-shutdownDone
+}
+
+// waitStopped blocks until all services are stopped or ctx is done. Returns true if all services stopped.
+func (c *Container) waitStopped(ctx context.Context) bool {
+	c.mu.Lock()
+	runContexts := c.orderedRunContexts()
+	attached := append([]*attachedTask{}, c.attached...)
+	c.mu.Unlock()
+
 	wg := sync.WaitGroup{}
-	wg.Add(len(c.runContexts))
-	for k := range c.runContexts {
-		rc := c.runContexts[k]
+	wg.Add(len(runContexts) + len(attached))
+	for _, rc := range runContexts {
+		go func() {
+			rc.wait(&c.mu)
+			wg.Done()
+		}()
+	}
+	for _, t := range attached {
 		go func() {
-			rc.wait()
-			c.onStopped(rc)
+			<-t.done
 			wg.Done()
 		}()
 	}
@@ -342,12 +956,18 @@ func (c *Container) WaitAllStopped(ctx context.Context) {
 
 	select {
 	case <-ctx.Done():
+		return false
 	case <-doneChan:
+		return true
 	}
 }
 
 // ServiceErrors returns all errors occurred in services
+// The map is keyed by "<container>/<service>", iterate ServiceNames for a stable order.
+// Use Errors to also get errors of Init and the shutdown including the lifecycle phase.
 func (c *Container) ServiceErrors() map[string]error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	errs := map[string]error{}
 	for _, rc := range c.runContexts {
 		if rc.err != nil {
@@ -360,28 +980,28 @@ func (c *Container) ServiceErrors() map[string]error {
 // onStopAll is called when all services get stopped
 // This method is only called once per container
 func (c *Container) onStopAll() {
-	for _, f := range c.shutdownCallbacks {
-		f()
+	c.mu.Lock()
+	callbacks := append([]shutdownCallback{}, c.shutdownCallbacks...)
+	c.mu.Unlock()
+	ctx := context.Background()
+	if c.runCtx != nil {
+		ctx = context.WithoutCancel(c.runCtx)
+	}
+	for i, cb := range callbacks {
+		c.runShutdownCallback(ctx, i, cb)
 	}
-}
-
-// onInit is called before a service Init method is called
-func (c *Container) onInit(s *serviceInfo) {
-
-}
-
-// onRun is called before a service Run method is called
-func (c *Container) onRun(s *serviceInfo) {
-
-}
-
-// onStopped is called after a service was stopped
-func (c *Container) onStopped(rc *runContext) {
-
 }
 
 // OnShutdown is called when the container is stopped and all services are going to be stopped
 // The callback is only called once per container
 func (c *Container) OnShutdown(f func()) {
-	c.shutdownCallbacks = append(c.shutdownCallbacks, f)
+	c.addShutdownCallback(func(ctx context.Context) {
+		f()
+	})
+}
+
+// OnShutdownContext is like OnShutdown but the callback receives the shutdown context.
+// The shutdown context carries the values of the run context (e.g. RunInfo) but is not canceled.
+func (c *Container) OnShutdownContext(f func(ctx context.Context)) {
+	c.addShutdownCallback(f)
 }
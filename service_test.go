@@ -389,3 +389,22 @@ func TestNotifyOnShutdown(t *testing.T) {
 	assert.True(t, ctxIsDone)
 	assert.Len(t, c.ServiceErrors(), 2)
 }
+
+func TestContext(t *testing.T) {
+	c := service.NewContainer()
+	assert.Panics(t, func() {
+		c.Context()
+	})
+	service.New("worker").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	ctx := c.Context()
+	info, ok := service.RunInfoFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, c.RunID(), info.RunID)
+	assert.NoError(t, ctx.Err())
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	assert.Error(t, ctx.Err())
+}
@@ -0,0 +1,97 @@
+// Package servicetest provides helpers to test implementations of service.Runner
+package servicetest
+
+import (
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+)
+
+type options struct {
+	runFor        time.Duration
+	timeout       time.Duration
+	dumpGoroutine bool
+}
+
+type Option func(o *options)
+
+// WithRunFor sets how long the runner is running before the context is canceled, default is 10ms
+func WithRunFor(d time.Duration) Option {
+	return func(o *options) {
+		o.runFor = d
+	}
+}
+
+// WithTimeout sets how long Run may take to return after the context was canceled, default is 1 second
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithGoroutineDump logs the stacks of all goroutines when Run does not return in time
+func WithGoroutineDump() Option {
+	return func(o *options) {
+		o.dumpGoroutine = true
+	}
+}
+
+// AssertCancelAware initializes (if the runner implements service.Initer) and runs the runner,
+// cancels its context and fails the test if Run does not return within the timeout.
+// Returns true if Run returned in time.
+func AssertCancelAware(t testing.TB, runner service.Runner, opts ...Option) bool {
+	t.Helper()
+	o := &options{
+		runFor:  10 * time.Millisecond,
+		timeout: time.Second,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if initer, ok := runner.(service.Initer); ok {
+		err := initer.Init(ctx)
+		if err != nil {
+			t.Errorf("Init failed: %s", err)
+			return false
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		// Returning early is fine, as long as the runner does not ignore the context
+		t.Logf("Run returned before the context was canceled: %v", err)
+		return true
+	case <-time.After(o.runFor):
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Logf("Run returned after cancel: %s", err)
+		}
+		return true
+	case <-time.After(o.timeout):
+	}
+
+	t.Errorf("Run did not return within %s after the context was canceled", o.timeout)
+	if o.dumpGoroutine {
+		sb := &strings.Builder{}
+		_ = pprof.Lookup("goroutine").WriteTo(sb, 2)
+		t.Logf("Goroutines:\n%s", sb.String())
+	}
+	return false
+}
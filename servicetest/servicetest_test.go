@@ -0,0 +1,54 @@
+package servicetest_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/niondir/go-service/servicetest"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingT records failures instead of failing the test
+type recordingT struct {
+	testing.TB
+	errors []string
+	logs   []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Logf(format string, args ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func TestAssertCancelAware(t *testing.T) {
+	runner := service.New("good").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}).Build()
+
+	assert.True(t, servicetest.AssertCancelAware(t, runner))
+}
+
+func TestAssertCancelAware_ignoresContext(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	runner := service.New("bad").Run(func(ctx context.Context) error {
+		<-stop
+		return nil
+	}).Build()
+
+	rt := &recordingT{TB: t}
+	ok := servicetest.AssertCancelAware(rt, runner, servicetest.WithTimeout(20*time.Millisecond), servicetest.WithGoroutineDump())
+	assert.False(t, ok)
+	assert.Len(t, rt.errors, 1)
+	assert.True(t, strings.HasPrefix(rt.logs[0], "Goroutines:"))
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrStopAll is the ShutdownCause when the shutdown was requested by calling StopAll
+var ErrStopAll = errors.New("StopAll")
+
+// ErrAllServicesStopped is the ShutdownCause when all services returned from Run on their own,
+// the run ends when WaitAllStopped observed it
+var ErrAllServicesStopped = errors.New("all services stopped")
+
+// ShutdownCause returns the reason why the container is shutting down, e.g. the error of the failed service,
+// ErrStopAll or the cause of the canceled parent context. Returns nil while the container runs.
+// The cause is also available to services with context.Cause on their context, see context.WithCancelCause.
+func (c *Container) ShutdownCause() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.runCtx == nil {
+		return nil
+	}
+	return context.Cause(c.runCtx)
+}
+
+// stopInOrder cancels the context of all running services grouped by their shutdown priority.
+// Groups with a higher priority are stopped first, the next group is only canceled after
+// all services of the previous group returned from Run.
+func (c *Container) stopInOrder() {
+	c.mu.Lock()
+	c.shuttingDown = true
+	reverse := c.reverseShutdown
+	var running []*runContext
+	for _, rc := range c.orderedRunContexts() {
+		if rc.running && rc.cancel != nil {
+			running = append(running, rc)
+		}
+	}
+	runCtx := c.runCtx
+	c.mu.Unlock()
+
+	ctx, endSpan := c.startSpan(context.WithoutCancel(runCtx), SpanShutdown, nil)
+	defer endSpan(nil)
+
+	if reverse {
+		c.stopReverse(ctx, running)
+		return
+	}
+
+	groups := map[int][]*runContext{}
+	for _, rc := range running {
+		p := rc.service.shutdownPriority
+		groups[p] = append(groups[p], rc)
+	}
+
+	priorities := make([]int, 0, len(groups))
+	for p := range groups {
+		priorities = append(priorities, p)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(priorities)))
+
+	for _, p := range priorities {
+		group := groups[p]
+		if len(priorities) > 1 {
+			c.log.Debug("Stopping services", "priority", p, "count", len(group), "container", c.name, "run", c.RunID())
+		}
+		wg := sync.WaitGroup{}
+		for _, rc := range group {
+			_, endStop := c.startSpan(ctx, SpanStop, rc.service)
+			rc.cancel()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				endStop(c.waitStop(rc))
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+// WithReverseOrderShutdown stops the services sequentially in reverse start order, which respects
+// dependencies, see Dependent. Each service is canceled only after the previously canceled service returned.
+// Shutdown priorities are ignored in this mode.
+func WithReverseOrderShutdown() Option {
+	return func(c *Container) {
+		c.reverseShutdown = true
+	}
+}
+
+// stopReverse cancels the services one by one in reverse start order
+func (c *Container) stopReverse(ctx context.Context, running []*runContext) {
+	slices.SortFunc(running, func(a, b *runContext) int {
+		return b.seq - a.seq
+	})
+	for _, rc := range running {
+		c.serviceLogger(rc.service).Debug("Stopping service")
+		_, endStop := c.startSpan(ctx, SpanStop, rc.service)
+		rc.cancel()
+		endStop(c.waitStop(rc))
+	}
+}
+
+// waitStop waits for the service to return from Run, at most the stop timeout of the service.
+// Returns an error when the stop timeout was exceeded.
+func (c *Container) waitStop(rc *runContext) error {
+	if rc.service.stopTimeout <= 0 {
+		<-rc.done
+		return nil
+	}
+	t := time.NewTimer(rc.service.stopTimeout)
+	defer t.Stop()
+	select {
+	case <-rc.done:
+		return nil
+	case <-t.C:
+		c.serviceLogger(rc.service).Warn("Service did not stop within stop timeout, continue shutdown", "timeout", rc.service.stopTimeout)
+		err := fmt.Errorf("service '%s' did not stop within %s: %w", rc.service.name, rc.service.stopTimeout, ErrStopTimeout)
+		c.recordError(rc.service, LifecycleShutdown, err)
+		return err
+	}
+}
+
+// StuckServices returns the names of services whose Run method did not return yet although the container
+// is shutting down, e.g. after WaitAllStopped returned because its ctx is done. Empty while the container runs.
+func (c *Container) StuckServices() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.shuttingDown {
+		return nil
+	}
+	var stuck []string
+	for _, rc := range c.orderedRunContexts() {
+		if rc.running {
+			stuck = append(stuck, rc.service.name)
+		}
+	}
+	return stuck
+}
+
+// logStuckServices logs every service that did not return from Run, see StuckServices
+func (c *Container) logStuckServices() {
+	for _, name := range c.StuckServices() {
+		c.mu.Lock()
+		rc := c.runContexts[name]
+		var since time.Duration
+		if !rc.canceledAt.IsZero() {
+			since = time.Since(rc.canceledAt)
+		}
+		c.mu.Unlock()
+		c.serviceLogger(rc.service).Error("Service did not stop", "canceled_since", since)
+	}
+}
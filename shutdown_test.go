@@ -0,0 +1,134 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stopRecorder records the order in which services returned from Run
+type stopRecorder struct {
+	mu    sync.Mutex
+	order []string
+}
+
+func (r *stopRecorder) run(name string, delay time.Duration) service.RunFunc {
+	return func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(delay)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.order = append(r.order, name)
+		return nil
+	}
+}
+
+func TestShutdownPriority(t *testing.T) {
+	c := service.NewContainer()
+	rec := &stopRecorder{}
+
+	service.New("api").Run(rec.run("api", 0)).Register(c)
+	service.New("batch").Run(rec.run("batch", 50*time.Millisecond)).ShutdownPriority(10).Register(c)
+	service.New("worker").Run(rec.run("worker", 0)).ShutdownPriority(5).Register(c)
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	assert.Equal(t, []string{"batch", "worker", "api"}, rec.order)
+}
+
+func TestShutdownPriority_parentContext(t *testing.T) {
+	c := service.NewContainer()
+	rec := &stopRecorder{}
+
+	service.New("api").Run(rec.run("api", 0)).Register(c)
+	service.New("batch").Run(rec.run("batch", 50*time.Millisecond)).ShutdownPriority(1).Register(c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := c.StartAll(ctx)
+	require.NoError(t, err)
+
+	cancel()
+	c.WaitAllStopped(context.Background())
+
+	assert.Equal(t, []string{"batch", "api"}, rec.order)
+}
+
+func TestReverseOrderShutdown(t *testing.T) {
+	c := service.NewContainer(service.WithReverseOrderShutdown())
+	rec := &stopRecorder{}
+
+	service.New("api").Run(rec.run("api", 0)).DependsOn("db").Register(c)
+	service.New("db").Run(rec.run("db", 0)).Register(c)
+	service.New("cache").Run(rec.run("cache", 20*time.Millisecond)).Register(c)
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	// Start order is db, api, cache
+	assert.Equal(t, []string{"cache", "api", "db"}, rec.order)
+}
+
+func TestStuckServices(t *testing.T) {
+	c := service.NewContainer()
+	release := make(chan struct{})
+	service.New("stuck").Run(func(ctx context.Context) error {
+		<-release
+		return nil
+	}).Register(c)
+	service.New("ok").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Empty(t, c.StuckServices())
+
+	c.StopAll()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	c.WaitAllStopped(ctx)
+	assert.Equal(t, []string{"stuck"}, c.StuckServices())
+
+	close(release)
+	c.WaitAllStopped(context.Background())
+	assert.Empty(t, c.StuckServices())
+}
+
+func TestShutdownCause(t *testing.T) {
+	c := service.NewContainer()
+	runErr := errors.New("crashed")
+	var serviceCause error
+	service.New("worker").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		serviceCause = context.Cause(ctx)
+		return nil
+	}).Register(c)
+	service.New("failing").Run(func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return runErr
+	}).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.NoError(t, c.ShutdownCause())
+
+	c.WaitAllStopped(context.Background())
+	assert.ErrorIs(t, c.ShutdownCause(), runErr)
+	assert.ErrorIs(t, serviceCause, runErr)
+}
+
+func TestShutdownCause_stopAll(t *testing.T) {
+	c := service.NewContainer()
+	service.New("worker").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	assert.ErrorIs(t, c.ShutdownCause(), service.ErrStopAll)
+}
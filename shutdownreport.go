@@ -0,0 +1,182 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ShutdownReport is the timeline of the last shutdown of a container, see Container.LastShutdownReport.
+// It helps to find the service that exceeded the termination grace period of a deployment.
+type ShutdownReport struct {
+	Container string
+	RunID     string
+	// Cause describes what triggered the shutdown, e.g. StopAll, a failed service or the parent context
+	Cause string
+	// RequestedAt is the time the shutdown was triggered
+	RequestedAt time.Time
+	// CanceledAt is the time the run context was canceled, after the shutdown callbacks and drain delay
+	CanceledAt time.Time
+	// FinishedAt is the time the last service returned or the stop timeouts exceeded, zero while in progress
+	FinishedAt time.Time
+	Callbacks  []CallbackTiming
+	// Services in the order they were canceled, services that were not canceled come last
+	Services []ServiceStopTiming
+}
+
+// CallbackTiming is the execution time of a single shutdown callback
+type CallbackTiming struct {
+	Index    int
+	Site     string
+	Start    time.Time
+	Duration time.Duration
+	Panicked bool
+}
+
+// ServiceStopTiming describes when a single service was canceled and returned from Run
+type ServiceStopTiming struct {
+	Name       string
+	CanceledAt time.Time
+	// StoppedAt is zero if the service did not return yet
+	StoppedAt time.Time
+	Err       error
+}
+
+// MarshalJSON renders Err as message, errors do not implement json.Marshaler
+func (s ServiceStopTiming) MarshalJSON() ([]byte, error) {
+	type timing ServiceStopTiming
+	errMsg := ""
+	if s.Err != nil {
+		errMsg = s.Err.Error()
+	}
+	return json.Marshal(struct {
+		timing
+		Err string `json:",omitempty"`
+	}{timing(s), errMsg})
+}
+
+// Duration of the shutdown from the request until all services stopped, 0 while in progress
+func (r *ShutdownReport) Duration() time.Duration {
+	if r.FinishedAt.IsZero() {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.RequestedAt)
+}
+
+// String renders the timeline with offsets relative to RequestedAt
+func (r *ShutdownReport) String() string {
+	sb := strings.Builder{}
+	offset := func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return "+" + t.Sub(r.RequestedAt).String()
+	}
+	sb.WriteString(fmt.Sprintf("Shutdown of container '%s' (run %s) caused by %s\n", r.Container, r.RunID, r.Cause))
+	for _, cb := range r.Callbacks {
+		suffix := ""
+		if cb.Panicked {
+			suffix = " (panicked)"
+		}
+		sb.WriteString(fmt.Sprintf("  %s callback %d at %s took %s%s\n", offset(cb.Start), cb.Index, cb.Site, cb.Duration, suffix))
+	}
+	sb.WriteString(fmt.Sprintf("  %s run context canceled\n", offset(r.CanceledAt)))
+	for _, s := range r.Services {
+		switch {
+		case s.StoppedAt.IsZero():
+			sb.WriteString(fmt.Sprintf("  %s %s canceled, still running\n", offset(s.CanceledAt), s.Name))
+		case s.Err != nil:
+			sb.WriteString(fmt.Sprintf("  %s %s stopped after %s: %s\n", offset(s.StoppedAt), s.Name, s.StoppedAt.Sub(s.CanceledAt), s.Err))
+		default:
+			sb.WriteString(fmt.Sprintf("  %s %s stopped after %s\n", offset(s.StoppedAt), s.Name, s.StoppedAt.Sub(s.CanceledAt)))
+		}
+	}
+	if r.FinishedAt.IsZero() {
+		sb.WriteString("  shutdown in progress\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("  %s finished, took %s\n", offset(r.FinishedAt), r.Duration()))
+	}
+	return sb.String()
+}
+
+// WithShutdownReportFile writes the ShutdownReport as JSON to the given file when the shutdown finished,
+// so it survives the process for post-mortems.
+func WithShutdownReportFile(path string) Option {
+	return func(c *Container) {
+		c.shutdownReportFile = path
+	}
+}
+
+// LastShutdownReport returns the timeline of the current or last shutdown, nil if the container was not stopped yet
+func (c *Container) LastShutdownReport() *ShutdownReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shutdownReport == nil {
+		return nil
+	}
+	r := *c.shutdownReport
+	r.Callbacks = append([]CallbackTiming{}, r.Callbacks...)
+	r.Services = nil
+	var notCanceled []ServiceStopTiming
+	for _, rc := range c.orderedRunContexts() {
+		if rc.startedAt.IsZero() {
+			continue
+		}
+		st := ServiceStopTiming{Name: rc.service.name, CanceledAt: rc.canceledAt, StoppedAt: rc.stoppedAt, Err: rc.err}
+		if rc.canceledAt.IsZero() {
+			notCanceled = append(notCanceled, st)
+		} else {
+			r.Services = append(r.Services, st)
+		}
+	}
+	slices.SortStableFunc(r.Services, func(a, b ServiceStopTiming) int {
+		return a.CanceledAt.Compare(b.CanceledAt)
+	})
+	r.Services = append(r.Services, notCanceled...)
+	return &r
+}
+
+// beginShutdown starts the shutdown report, only the first call per run is recorded
+func (c *Container) beginShutdown(cause string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shutdownReport != nil && c.shutdownReport.RunID == c.runInfo.RunID {
+		return
+	}
+	c.shutdownReport = &ShutdownReport{
+		Container:   c.name,
+		RunID:       c.runInfo.RunID,
+		Cause:       cause,
+		RequestedAt: time.Now(),
+	}
+}
+
+// recordShutdown updates the current shutdown report
+func (c *Container) recordShutdown(f func(r *ShutdownReport)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shutdownReport != nil {
+		f(c.shutdownReport)
+	}
+}
+
+// finishShutdown completes the shutdown report and writes it to the report file if configured
+func (c *Container) finishShutdown() {
+	c.recordShutdown(func(r *ShutdownReport) {
+		r.FinishedAt = time.Now()
+	})
+	if c.shutdownReportFile == "" {
+		return
+	}
+	report := c.LastShutdownReport()
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err == nil {
+		err = os.WriteFile(c.shutdownReportFile, data, 0o644)
+	}
+	if err != nil {
+		c.log.Error("Failed to write shutdown report", "file", c.shutdownReportFile, "error", err, "container", c.name)
+	}
+}
@@ -0,0 +1,48 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastShutdownReport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "shutdown.json")
+	c := service.NewContainer(service.WithShutdownReportFile(file))
+	c.OnShutdown(func() {})
+	rec := &stopRecorder{}
+	service.New("api").Run(rec.run("api", 0)).Register(c)
+	service.New("batch").Run(rec.run("batch", 30*time.Millisecond)).ShutdownPriority(1).Register(c)
+
+	assert.Nil(t, c.LastShutdownReport())
+	require.NoError(t, c.StartAll(context.Background()))
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	require.Eventually(t, func() bool {
+		r := c.LastShutdownReport()
+		return r != nil && !r.FinishedAt.IsZero()
+	}, time.Second, 5*time.Millisecond)
+	report := c.LastShutdownReport()
+	assert.Equal(t, "StopAll", report.Cause)
+	assert.Len(t, report.Callbacks, 1)
+	require.Len(t, report.Services, 2)
+	assert.Equal(t, "batch", report.Services[0].Name)
+	assert.Equal(t, "api", report.Services[1].Name)
+	assert.GreaterOrEqual(t, report.Duration(), 30*time.Millisecond)
+	assert.Contains(t, report.String(), "batch stopped after")
+
+	var written map[string]any
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(file)
+		return err == nil && json.Unmarshal(data, &written) == nil
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "StopAll", written["Cause"])
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+)
+
+// RunUntilSignal starts all services and blocks until one of the signals is received, ctx is done
+// or a service failed. Afterward all services are stopped and awaited.
+// Without signals, SIGINT and SIGTERM are handled. The signals are handled like the cancellation of the parent
+// context of StartAll, see WithStopOnParentCancelDelay. Once the shutdown began, the signals are no longer handled,
+// thus a second signal terminates the process.
+// Returns the error of StartAll or the errors of all failed services.
+func (c *Container) RunUntilSignal(ctx context.Context, signals ...os.Signal) error {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	sigCtx, stop := signal.NotifyContext(ctx, signals...)
+	defer stop()
+
+	if err := c.StartAll(sigCtx); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	runCtx := c.runCtx
+	c.mu.Unlock()
+
+	<-runCtx.Done()
+	if ctx.Err() == nil && sigCtx.Err() != nil {
+		c.log.Info("Received signal, stopping services", "cause", context.Cause(sigCtx), "container", c.name)
+	}
+	// Restore the default signal behavior, a second signal kills the process when the shutdown hangs
+	stop()
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	serviceErrs := c.ServiceErrors()
+	names := make([]string, 0, len(serviceErrs))
+	for name := range serviceErrs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, fmt.Errorf("%s: %w", name, serviceErrs[name]))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,43 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunUntilSignal(t *testing.T) {
+	c := service.NewContainer()
+	stopped := false
+	service.New("worker").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		stopped = true
+		return nil
+	}).Register(c)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		p, _ := os.FindProcess(os.Getpid())
+		_ = p.Signal(os.Interrupt)
+	}()
+	err := c.RunUntilSignal(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, stopped)
+}
+
+func TestRunUntilSignal_serviceFails(t *testing.T) {
+	c := service.NewContainer()
+	runErr := errors.New("failed")
+	service.New("failing").Run(func(ctx context.Context) error {
+		return runErr
+	}).Register(c)
+	service.New("worker").Run(blockUntilDone).Register(c)
+
+	err := c.RunUntilSignal(context.Background())
+	assert.ErrorIs(t, err, runErr)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Standby returns a service that keeps a warm standby instance of the service created by newService.
+// The standby is created and initialized in the background once the active instance runs.
+// When Run of the active instance returns an error while the container is running, the standby is
+// promoted and run immediately, a new standby is prepared in the background. When no standby is available
+// the error of the active instance is returned like for any other service.
+// The name and the optional interfaces, e.g. Dependent or Tagger, are taken from the first instance,
+// Readier and HealthChecker are forwarded to the active instance.
+func Standby(newService func() Runner) Runner {
+	return &standbyService{
+		newService: newService,
+		active:     newService(),
+	}
+}
+
+type standbyService struct {
+	newService func() Runner
+
+	mu     sync.Mutex
+	active Runner
+	// standby is the result of the current preparation, nil until prepared is closed
+	standby    Runner
+	standbyErr error
+	prepared   chan struct{}
+}
+
+// primary returns the first instance, used for the name and optional interfaces, see newServiceInfo
+func (s *standbyService) primary() Runner {
+	return s.active
+}
+
+func (s *standbyService) String() string {
+	if str, ok := s.active.(fmt.Stringer); ok {
+		return str.String()
+	}
+	return fmt.Sprintf("%T", s.active)
+}
+
+func (s *standbyService) Init(ctx context.Context) error {
+	return initInstance(ctx, s.activeInstance())
+}
+
+func (s *standbyService) Run(ctx context.Context) error {
+	logger := LoggerFromContext(ctx)
+	s.prepareStandby(ctx)
+	for {
+		err := s.activeInstance().Run(ctx)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		prepared := s.prepared
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return err
+		case <-prepared:
+		}
+		s.mu.Lock()
+		standby, standbyErr := s.standby, s.standbyErr
+		if standby != nil {
+			s.active = standby
+		}
+		s.mu.Unlock()
+		if standby == nil {
+			return errors.Join(err, fmt.Errorf("no standby available: %w", standbyErr))
+		}
+		logger.Warn("Active instance failed, promoted standby", "error", err)
+		s.prepareStandby(ctx)
+	}
+}
+
+// prepareStandby creates and initializes a new standby instance in the background
+func (s *standbyService) prepareStandby(ctx context.Context) {
+	prepared := make(chan struct{})
+	s.mu.Lock()
+	s.standby, s.standbyErr = nil, nil
+	s.prepared = prepared
+	s.mu.Unlock()
+	go func() {
+		defer close(prepared)
+		standby := s.newService()
+		err := initInstance(ctx, standby)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err != nil {
+			LoggerFromContext(ctx).Warn("Failed to initialize standby", "error", err)
+			s.standbyErr = err
+			return
+		}
+		s.standby = standby
+	}()
+}
+
+func (s *standbyService) activeInstance() Runner {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active
+}
+
+// Ready forwards to the active instance, see Readier
+func (s *standbyService) Ready(ctx context.Context) error {
+	if r := readierOf(s.activeInstance()); r != nil {
+		return r.Ready(ctx)
+	}
+	return nil
+}
+
+// Health forwards to the active instance, see HealthChecker
+func (s *standbyService) Health(ctx context.Context) error {
+	if h, ok := s.activeInstance().(HealthChecker); ok {
+		return h.Health(ctx)
+	}
+	return nil
+}
+
+func initInstance(ctx context.Context, r Runner) error {
+	if initer, ok := r.(Initer); ok {
+		return initer.Init(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandby(t *testing.T) {
+	c := service.NewContainer()
+	var instances, inits atomic.Int32
+	c.Register(service.Standby(func() service.Runner {
+		instance := instances.Add(1)
+		return service.New("singleton").Init(func(ctx context.Context) error {
+			inits.Add(1)
+			return nil
+		}).Run(func(ctx context.Context) error {
+			if instance == 1 {
+				return errors.New("lost connection")
+			}
+			<-ctx.Done()
+			return nil
+		}).Tags("singleton").Build()
+	}))
+	assert.Equal(t, "singleton", c.Status()[0].Name)
+	assert.Equal(t, []string{"singleton"}, c.Status()[0].Tags)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	assert.Eventually(t, func() bool {
+		return instances.Load() == 3 && inits.Load() == 3
+	}, time.Second, 5*time.Millisecond, "standby promoted and a new standby prepared")
+	assert.Equal(t, 1, c.RunningCount())
+	assert.Equal(t, service.ContainerRunning, c.State())
+
+	assert.NoError(t, c.StopAllAndWait(context.Background()))
+}
+
+func TestStandby_noStandby(t *testing.T) {
+	c := service.NewContainer()
+	var instances atomic.Int32
+	runErr := errors.New("lost connection")
+	c.Register(service.Standby(func() service.Runner {
+		if instances.Add(1) > 1 {
+			return service.New("singleton").Init(func(ctx context.Context) error {
+				return errors.New("no capacity")
+			}).Build()
+		}
+		return service.New("singleton").Run(func(ctx context.Context) error {
+			return runErr
+		}).Build()
+	}))
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.ErrorIs(t, c.WaitAllStopped(context.Background()), runErr)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+// StartStopper is implemented by legacy components that are started and stopped explicitly
+type StartStopper interface {
+	// Start starts the component in the background and returns
+	Start() error
+	// Stop stops the component and returns when it is stopped
+	Stop() error
+}
+
+// FromStartStopper returns a service that calls Start in Run and Stop when the context of Run is canceled,
+// to run legacy components in the container. Start must not block.
+// When Start fails, Stop is not called and Run returns the error.
+func FromStartStopper(name string, s StartStopper) Runner {
+	return &genericService{
+		name: name,
+		run: func(ctx context.Context) error {
+			if err := s.Start(); err != nil {
+				return fmt.Errorf("failed to start: %w", err)
+			}
+			<-ctx.Done()
+			if err := s.Stop(); err != nil {
+				return fmt.Errorf("failed to stop: %w", err)
+			}
+			return nil
+		},
+	}
+}
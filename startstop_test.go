@@ -0,0 +1,57 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type legacyComponent struct {
+	startErr error
+	stopErr  error
+	calls    []string
+}
+
+func (l *legacyComponent) Start() error {
+	l.calls = append(l.calls, "start")
+	return l.startErr
+}
+
+func (l *legacyComponent) Stop() error {
+	l.calls = append(l.calls, "stop")
+	return l.stopErr
+}
+
+func TestFromStartStopper(t *testing.T) {
+	l := &legacyComponent{}
+	c := service.NewContainer()
+	c.Register(service.FromStartStopper("legacy", l))
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Equal(t, []string{"legacy"}, c.ServiceNames())
+
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+	assert.Equal(t, []string{"start", "stop"}, l.calls)
+}
+
+func TestFromStartStopper_errors(t *testing.T) {
+	startErr := errors.New("port in use")
+	l := &legacyComponent{startErr: startErr}
+	c := service.NewContainer()
+	c.Register(service.FromStartStopper("legacy", l))
+	require.NoError(t, c.StartAll(context.Background()))
+	err := c.WaitAllStopped(context.Background())
+	assert.ErrorIs(t, err, startErr)
+	assert.Equal(t, []string{"start"}, l.calls)
+
+	stopErr := errors.New("flush failed")
+	l = &legacyComponent{stopErr: stopErr}
+	c = service.NewContainer()
+	c.Register(service.FromStartStopper("legacy", l))
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.ErrorIs(t, c.StopAllAndWait(context.Background()), stopErr)
+	assert.Equal(t, []string{"start", "stop"}, l.calls)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrStartupTimeout is wrapped by the error of a service that did not become ready within its startup timeout,
+// see WithStartupTimeout
+var ErrStartupTimeout = errors.New("startup timeout exceeded")
+
+// WithStartupTimeout limits the time a service may take to become ready for the first time after Run was called,
+// like a startup probe. Readiness is reported by Readier, later readiness checks are not limited.
+// When exceeded, the context of Run is canceled with a cause wrapping ErrStartupTimeout and the service fails.
+func WithStartupTimeout(d time.Duration) RegisterOption {
+	return func(s *serviceInfo) {
+		s.startupTimeout = d
+	}
+}
+
+// probeStartup waits until the service is ready for the first time and records the time, see ServiceStatus.ReadyAt.
+// When the startup timeout is exceeded or the service can not become ready, cancel is called with
+// a cause wrapping ErrStartupTimeout.
+func (c *Container) probeStartup(ctx context.Context, rc *runContext, cancel context.CancelCauseFunc) {
+	s := rc.service
+	readier := c.readierFor(rc)
+	if readier != nil {
+		probeCtx := ctx
+		if s.startupTimeout > 0 {
+			var cancelProbe context.CancelFunc
+			probeCtx, cancelProbe = context.WithTimeout(ctx, s.startupTimeout)
+			defer cancelProbe()
+		}
+		err := readier.Ready(probeCtx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if s.startupTimeout > 0 {
+				cancel(fmt.Errorf("%w, service '%s' not ready within %s: %w", ErrStartupTimeout, s.name, s.startupTimeout, err))
+			} else {
+				c.serviceLogger(s).Warn("Service can not become ready", "error", err)
+			}
+			return
+		}
+	}
+
+	c.mu.Lock()
+	if rc.readyAt.IsZero() {
+		rc.readyAt = time.Now()
+	}
+	startup := rc.readyAt.Sub(rc.startedAt)
+	c.mu.Unlock()
+	c.serviceLogger(s).Debug("Service ready", "startup_duration", startup)
+	c.notifyStatusChange()
+}
@@ -0,0 +1,115 @@
+package service
+
+// ContainerState describes in which phase of the lifecycle the container currently is
+type ContainerState int
+
+const (
+	// ContainerCreated containers were not started yet
+	ContainerCreated ContainerState = iota
+	// ContainerStarting containers execute StartAll
+	ContainerStarting
+	// ContainerRunning containers started all services
+	ContainerRunning
+	// ContainerStopping containers were stopped, but not all services returned from Run yet
+	ContainerStopping
+	// ContainerStopped containers were stopped and all services returned from Run
+	ContainerStopped
+	// ContainerFailed containers were stopped because the startup or a critical service failed,
+	// all services returned from Run
+	ContainerFailed
+)
+
+func (s ContainerState) String() string {
+	switch s {
+	case ContainerCreated:
+		return "Created"
+	case ContainerStarting:
+		return "Starting"
+	case ContainerRunning:
+		return "Running"
+	case ContainerStopping:
+		return "Stopping"
+	case ContainerStopped:
+		return "Stopped"
+	case ContainerFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// State returns the current state of the container
+func (c *Container) State() ContainerState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.runCtx == nil {
+		return ContainerCreated
+	}
+	if !c.stopRequested.Load() && c.runCtx.Err() == nil {
+		if c.starting {
+			return ContainerStarting
+		}
+		return ContainerRunning
+	}
+	failed := c.startReport != nil && c.startReport.Err != nil
+	for _, rc := range c.runContexts {
+		if rc.running {
+			return ContainerStopping
+		}
+		if rc.err != nil && rc.service.critical {
+			failed = true
+		}
+	}
+	if failed {
+		return ContainerFailed
+	}
+	return ContainerStopped
+}
+
+// OnStateChange registers a callback that is called on every state transition of the container,
+// e.g. to track many containers without polling State. Transitions are delivered in order, one at a time.
+// Short-lived states might be skipped, e.g. when all services stopped right away the transition
+// is from ContainerRunning to ContainerStopped.
+func (c *Container) OnStateChange(f func(from, to ContainerState)) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	c.stateCallbacks = append(c.stateCallbacks, f)
+}
+
+type stateTransition struct {
+	from, to ContainerState
+}
+
+// updateState compares the current state with the last reported state and notifies the OnStateChange callbacks.
+// Must be called after every change that might affect the State.
+func (c *Container) updateState() {
+	state := c.State()
+	c.stateMu.Lock()
+	if state == c.lastState {
+		c.stateMu.Unlock()
+		return
+	}
+	c.pendingStates = append(c.pendingStates, stateTransition{from: c.lastState, to: state})
+	c.lastState = state
+	if c.dispatchingStates {
+		// The active dispatcher delivers the transition, this also avoids deadlocks when callbacks change the state
+		c.stateMu.Unlock()
+		return
+	}
+	c.dispatchingStates = true
+	for len(c.pendingStates) > 0 {
+		t := c.pendingStates[0]
+		c.pendingStates = c.pendingStates[1:]
+		callbacks := append([]func(from, to ContainerState){}, c.stateCallbacks...)
+		c.stateMu.Unlock()
+		c.log.Debug("Container state changed", "from", t.from, "to", t.to, "container", c.name)
+		for _, f := range callbacks {
+			c.callSafe("state change callback", func() {
+				f(t.from, t.to)
+			})
+		}
+		c.stateMu.Lock()
+	}
+	c.dispatchingStates = false
+	c.stateMu.Unlock()
+}
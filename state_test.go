@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState(t *testing.T) {
+	c := service.NewContainer()
+	release := make(chan struct{})
+	service.New("slow").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		<-release
+		return nil
+	}).Register(c)
+	assert.Equal(t, service.ContainerCreated, c.State())
+
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Equal(t, service.ContainerRunning, c.State())
+
+	c.StopAll()
+	assert.Equal(t, service.ContainerStopping, c.State())
+
+	close(release)
+	c.WaitAllStopped(context.Background())
+	assert.Equal(t, service.ContainerStopped, c.State())
+}
+
+func TestStopAll_concurrent(t *testing.T) {
+	c := service.NewContainer()
+	calls := 0
+	c.OnShutdown(func() {
+		calls++
+	})
+	service.New("worker").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.StopAll()
+		}()
+	}
+	wg.Wait()
+	c.WaitAllStopped(context.Background())
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, service.ContainerStopped, c.State())
+}
+
+func TestOnStateChange(t *testing.T) {
+	c := service.NewContainer()
+	var mu sync.Mutex
+	var transitions []string
+	c.OnStateChange(func(from, to service.ContainerState) {
+		mu.Lock()
+		defer mu.Unlock()
+		transitions = append(transitions, from.String()+"->"+to.String())
+	})
+	service.New("worker").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		return errors.New("failed on shutdown")
+	}).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(transitions) == 4
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"Created->Starting", "Starting->Running", "Running->Stopping", "Stopping->Failed"}, transitions)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Stats is a summary of the state of a container, see Container.Stats
+type Stats struct {
+	Container string
+	State     ContainerState
+	// Services is the number of registered services
+	Services int
+	// States counts the services by state, states without services are omitted
+	States map[ServiceState]int
+	// Restarts of all services, see WithRestartPolicy
+	Restarts int
+	// Errors is the number of errors of the current run, see Container.Errors
+	Errors int
+	// MeanInitDuration of all services that were initialized in the current run
+	MeanInitDuration time.Duration
+	// Uptime is the time since StartAll was called, 0 when the container is not running
+	Uptime time.Duration
+}
+
+// String formats the stats as single line, e.g. for periodic logging
+func (s Stats) String() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "container '%s' %s: %d services", s.Container, s.State, s.Services)
+	for _, state := range []ServiceState{StateRegistered, StateInitializing, StateWarming, StateRunning, StateStopping, StateStopped, StateFailed, StateAbandoned} {
+		if n := s.States[state]; n > 0 {
+			fmt.Fprintf(b, ", %d %s", n, strings.ToLower(state.String()))
+		}
+	}
+	fmt.Fprintf(b, ", %d restarts, %d errors, mean init %s, uptime %s", s.Restarts, s.Errors, s.MeanInitDuration, s.Uptime.Truncate(time.Second))
+	return b.String()
+}
+
+// WithStatsLogInterval logs the Stats of the container in the given interval while it is running
+func WithStatsLogInterval(d time.Duration) Option {
+	return func(c *Container) {
+		c.statsLogInterval = d
+	}
+}
+
+// Stats returns a summary of the services of the container, see Status for details per service
+func (c *Container) Stats() Stats {
+	status := c.Status()
+	state := c.State()
+	c.mu.Lock()
+	stats := Stats{
+		Container: c.name,
+		State:     state,
+		States:    map[ServiceState]int{},
+		Errors:    len(c.serviceErrors),
+	}
+	if state == ContainerStarting || state == ContainerRunning || state == ContainerStopping {
+		stats.Uptime = time.Since(c.runInfo.StartedAt)
+	}
+	c.mu.Unlock()
+
+	var initTotal time.Duration
+	initialized := 0
+	for _, s := range status {
+		if s.Attached {
+			continue
+		}
+		stats.Services++
+		stats.States[s.State]++
+		stats.Restarts += s.Restarts
+		if s.State != StateRegistered && s.State != StateInitializing {
+			initTotal += s.InitDuration
+			initialized++
+		}
+	}
+	if initialized > 0 {
+		stats.MeanInitDuration = initTotal / time.Duration(initialized)
+	}
+	return stats
+}
+
+// logStats logs the stats in the configured interval until ctx is done, see WithStatsLogInterval
+func (c *Container) logStats(ctx context.Context) {
+	t := time.NewTicker(c.statsLogInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		c.log.Info(c.Stats().String(), "container", c.name, "run", c.RunID())
+	}
+}
@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	c := service.NewContainer(service.WithName("app"))
+	service.New("db").Init(func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}).Run(blockUntilDone).Register(c)
+	service.New("api").Run(blockUntilDone).Register(c)
+	service.New("job").Run(func(ctx context.Context) error {
+		return errors.New("failed")
+	}).Critical(false).Register(c)
+
+	stats := c.Stats()
+	assert.Equal(t, 3, stats.Services)
+	assert.Equal(t, map[service.ServiceState]int{service.StateRegistered: 3}, stats.States)
+	assert.Zero(t, stats.Uptime)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	require.Eventually(t, func() bool {
+		return c.Stats().States[service.StateFailed] == 1
+	}, time.Second, time.Millisecond)
+	stats = c.Stats()
+	assert.Equal(t, "app", stats.Container)
+	assert.Equal(t, service.ContainerRunning, stats.State)
+	assert.Equal(t, map[service.ServiceState]int{service.StateRunning: 2, service.StateFailed: 1}, stats.States)
+	assert.Equal(t, 1, stats.Errors)
+	assert.GreaterOrEqual(t, stats.MeanInitDuration, 10*time.Millisecond/3)
+	assert.Positive(t, stats.Uptime)
+	assert.Contains(t, stats.String(), "container 'app' Running: 3 services, 2 running, 1 failed, 0 restarts, 1 errors")
+
+	_ = c.StopAllAndWait(context.Background())
+	assert.Zero(t, c.Stats().Uptime)
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWithStatsLogInterval(t *testing.T) {
+	out := &syncBuffer{}
+	c := service.NewContainer(service.WithName("app"), service.WithStatsLogInterval(5*time.Millisecond))
+	c.SetLogger(slog.New(slog.NewTextHandler(out, nil)))
+	service.New("api").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "container 'app' Running: 1 services, 1 running")
+	}, time.Second, time.Millisecond)
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
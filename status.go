@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ServiceState describes in which phase of the lifecycle a service currently is
+type ServiceState int
+
+const (
+	// StateRegistered services are registered but not started yet
+	StateRegistered ServiceState = iota
+	// StateInitializing services are initializing or wait for other services to initialize before they run
+	StateInitializing
+	// StateRunning services are executing their Run method
+	StateRunning
+	// StateStopped services returned from Run without error or were never run because the startup failed
+	StateStopped
+	// StateFailed services returned an error from Init or Run
+	StateFailed
+	// StateAbandoned services did not return from Run during the shutdown and were abandoned, see Container.Abandon
+	StateAbandoned
+	// StateStopping services were canceled but did not return from Run yet
+	StateStopping
+	// StateWarming services are running while their background init is not done yet, see Builder.BackgroundInit
+	StateWarming
+)
+
+func (s ServiceState) String() string {
+	switch s {
+	case StateRegistered:
+		return "Registered"
+	case StateInitializing:
+		return "Initializing"
+	case StateRunning:
+		return "Running"
+	case StateStopped:
+		return "Stopped"
+	case StateFailed:
+		return "Failed"
+	case StateAbandoned:
+		return "Abandoned"
+	case StateStopping:
+		return "Stopping"
+	case StateWarming:
+		return "Warming"
+	default:
+		return "Unknown"
+	}
+}
+
+// ServiceStatus is a snapshot of the state of a single service
+type ServiceStatus struct {
+	Name  string
+	State ServiceState
+	// Tags of the service, see Tagger
+	Tags []string
+	// Capabilities are the optional interfaces implemented by the service, see Container.Capabilities
+	Capabilities []Capability
+	// LockedOSThread is set when Run is locked to an OS thread, see WithLockedOSThread
+	LockedOSThread bool
+	// Err returned by Run or Init
+	Err error
+	// ErrCode is the code of Err, see ErrorCodeOf
+	ErrCode ErrorCode
+	// Restarts of the service in the current run, see WithRestartPolicy, WithSupervisor and Container.Restart
+	Restarts int
+	// StartedAt is the time Run was called, StoppedAt the time Run returned. Zero if not yet happened.
+	StartedAt time.Time
+	StoppedAt time.Time
+	// InitDuration is the time Init took
+	InitDuration time.Duration
+	// RunDuration is the time since Run was called or the time Run took after it returned
+	RunDuration time.Duration
+	// ReadyAt is the time the service became ready for the first time, zero if not yet ready, see Readier.
+	// StartupDuration is the time from calling Run until ReadyAt, see WithStartupTimeout.
+	ReadyAt         time.Time
+	StartupDuration time.Duration
+	// StopDuration is the time from canceling the service until Run returned
+	StopDuration time.Duration
+	// Goroutines labeled with the service, only set when WithResourceStats is enabled
+	Goroutines int
+	// CPU time used by the service during the last SampleCPU call
+	CPU time.Duration
+	// Attached is true for tasks started with GoAttached, they are listed after all services
+	Attached bool
+}
+
+// statusDebounce is the time WatchStatus waits for further changes before emitting a new snapshot
+const statusDebounce = 50 * time.Millisecond
+
+// reportedState returns the state of the service including the warming phase, see StateWarming.
+// c.mu must be held by the caller.
+func (rc *runContext) reportedState() ServiceState {
+	if w, ok := rc.service.service.(interface{ warming() bool }); ok && rc.state == StateRunning && w.warming() {
+		return StateWarming
+	}
+	return rc.state
+}
+
+// IsServiceRunning returns true if the Run method of the service has not returned yet
+func (c *Container) IsServiceRunning(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rc, ok := c.runContexts[name]
+	return ok && rc.running
+}
+
+// ServiceState returns the state of a single service, see Status.
+// Returns an error if the service is not registered.
+func (c *Container) ServiceState(name string) (ServiceState, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if rc, ok := c.runContexts[name]; ok {
+		return rc.reportedState(), nil
+	}
+	if slices.ContainsFunc(c.services, func(s *serviceInfo) bool { return s.name == name }) {
+		return StateRegistered, nil
+	}
+	return StateRegistered, fmt.Errorf("service '%s' not registered in container '%s'", name, c.name)
+}
+
+// Status returns a snapshot of the state of all registered services in the order defined by WithReportOrder
+func (c *Container) Status() []ServiceStatus {
+	var goroutines map[string]int
+	if c.resourceStats {
+		goroutines = c.labeledGoroutines()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := make([]ServiceStatus, 0, len(c.services))
+	for _, s := range c.orderedServices() {
+		st := ServiceStatus{
+			Name:           s.name,
+			State:          StateRegistered,
+			Tags:           s.tags,
+			Capabilities:   capabilitiesOf(s.service),
+			LockedOSThread: s.lockOSThread,
+			Goroutines:     goroutines[s.name],
+			CPU:            c.cpuUsage[s.name],
+		}
+		if rc, ok := c.runContexts[s.name]; ok {
+			st.State = rc.reportedState()
+			switch {
+			case rc.err != nil:
+				st.Err = rc.err
+				st.ErrCode = ErrorCodeOf(LifecycleRun, rc.err)
+			case rc.initErr != nil:
+				st.Err = rc.initErr
+				st.ErrCode = ErrorCodeOf(LifecycleInit, rc.initErr)
+			}
+			st.Restarts = rc.restarts
+			st.StartedAt = rc.startedAt
+			st.StoppedAt = rc.stoppedAt
+			st.InitDuration = rc.initDuration
+			if !rc.readyAt.IsZero() {
+				st.ReadyAt = rc.readyAt
+				st.StartupDuration = rc.readyAt.Sub(rc.startedAt)
+			}
+			if !rc.startedAt.IsZero() {
+				if rc.stoppedAt.IsZero() {
+					st.RunDuration = time.Since(rc.startedAt)
+				} else {
+					st.RunDuration = rc.stoppedAt.Sub(rc.startedAt)
+				}
+			}
+			if !rc.canceledAt.IsZero() && !rc.stoppedAt.IsZero() {
+				st.StopDuration = max(rc.stoppedAt.Sub(rc.canceledAt), 0)
+			}
+		}
+		status = append(status, st)
+	}
+	return append(status, c.attachedStatus()...)
+}
+
+// WatchStatus emits a snapshot of Status whenever the state of any service changes.
+// The first snapshot is emitted right away. Changes in quick succession are debounced into a single snapshot.
+// Snapshots are dropped when the receiver is too slow, the latest snapshot is always delivered.
+// The channel is closed when ctx is done.
+func (c *Container) WatchStatus(ctx context.Context) <-chan []ServiceStatus {
+	out := make(chan []ServiceStatus, 1)
+	notify := make(chan struct{}, 1)
+	c.mu.Lock()
+	c.statusWatchers[notify] = struct{}{}
+	c.mu.Unlock()
+
+	go func() {
+		defer close(out)
+		defer func() {
+			c.mu.Lock()
+			delete(c.statusWatchers, notify)
+			c.mu.Unlock()
+		}()
+
+		send := func() {
+			snapshot := c.Status()
+			// Replace a snapshot the receiver did not pick up yet
+			select {
+			case <-out:
+			default:
+			}
+			out <- snapshot
+		}
+
+		send()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-notify:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(statusDebounce):
+			}
+			// Changes during the debounce time are part of this snapshot
+			select {
+			case <-notify:
+			default:
+			}
+			send()
+		}
+	}()
+	return out
+}
+
+// setState changes the state of a service and notifies all status watchers and event subscribers
+func (c *Container) setState(rc *runContext, state ServiceState) {
+	c.mu.Lock()
+	err := rc.err
+	c.mu.Unlock()
+	c.setStateErr(rc, state, err)
+}
+
+// setStateErr is like setState, the error is reported with the event
+func (c *Container) setStateErr(rc *runContext, state ServiceState, err error) {
+	c.mu.Lock()
+	rc.state = state
+	phase := LifecycleRun
+	if rc.startedAt.IsZero() {
+		phase = LifecycleInit
+	}
+	c.mu.Unlock()
+	c.notifyStatusChange()
+	c.emitPhaseEvent(eventTypeOf(state), rc.service.name, phase, err)
+}
+
+// markNotStarted sets all services that were initialized but never run to stopped
+func (c *Container) markNotStarted() {
+	c.mu.Lock()
+	var notStarted []string
+	for _, rc := range c.runContexts {
+		if rc.state == StateInitializing && !rc.running {
+			rc.state = StateStopped
+			notStarted = append(notStarted, rc.service.name)
+		}
+	}
+	c.mu.Unlock()
+	c.notifyStatusChange()
+	for _, name := range notStarted {
+		c.emitEvent(EventStopped, name, nil)
+	}
+}
+
+func (c *Container) notifyStatusChange() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for notify := range c.statusWatchers {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	c := service.NewContainer()
+	s1 := &testService{Name: "s1"}
+	c.Register(s1)
+	s2 := &testService{Name: "s2", ErrorDuringRun: errors.New("failed")}
+	c.Register(s2)
+
+	status := c.Status()
+	require.Len(t, status, 2)
+	assert.Equal(t, service.StateRegistered, status[0].State)
+	assert.Equal(t, service.StateRegistered, status[1].State)
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	c.WaitAllStopped(context.Background())
+
+	status = c.Status()
+	assert.Equal(t, s1.String(), status[0].Name)
+	assert.Equal(t, service.StateStopped, status[0].State)
+	assert.NoError(t, status[0].Err)
+	assert.Equal(t, s2.String(), status[1].Name)
+	assert.Equal(t, service.StateFailed, status[1].State)
+	assert.Error(t, status[1].Err)
+}
+
+func TestStatus_initFailed(t *testing.T) {
+	c := service.NewContainer()
+	initErr := errors.New("no config")
+	service.New("api").Init(func(ctx context.Context) error {
+		return initErr
+	}).Run(blockUntilDone).Register(c)
+
+	require.Error(t, c.StartAll(context.Background()))
+	status := c.Status()
+	assert.Equal(t, service.StateFailed, status[0].State)
+	assert.ErrorIs(t, status[0].Err, initErr)
+	assert.Equal(t, service.CodeInitFailed, status[0].ErrCode)
+}
+
+func TestWatchStatus(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(&testService{Name: "s1"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watch := c.WatchStatus(ctx)
+
+	initial := <-watch
+	require.Len(t, initial, 1)
+	assert.Equal(t, service.StateRegistered, initial[0].State)
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+
+	waitForState := func(state service.ServiceState) {
+		t.Helper()
+		timeout := time.After(time.Second)
+		for {
+			select {
+			case status := <-watch:
+				if status[0].State == state {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timeout waiting for state %s", state)
+			}
+		}
+	}
+	waitForState(service.StateRunning)
+
+	c.StopAll()
+	waitForState(service.StateStopped)
+
+	cancel()
+	for range watch {
+		// Drain until closed
+	}
+}
+
+func TestStatus_timing(t *testing.T) {
+	c := service.NewContainer()
+	service.New("worker").Init(func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}).Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}).Register(c)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	st := c.Status()[0]
+	assert.False(t, st.StartedAt.IsZero())
+	assert.True(t, st.StoppedAt.IsZero())
+	assert.GreaterOrEqual(t, st.InitDuration, 10*time.Millisecond)
+	assert.Positive(t, st.RunDuration)
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	st = c.Status()[0]
+	assert.False(t, st.StoppedAt.IsZero())
+	assert.GreaterOrEqual(t, st.StopDuration, 10*time.Millisecond)
+	assert.Equal(t, st.StoppedAt.Sub(st.StartedAt), st.RunDuration)
+	assert.Equal(t, st.RunDuration, c.Status()[0].RunDuration, "run duration is fixed after stop")
+}
+
+func TestServiceState(t *testing.T) {
+	c := service.NewContainer()
+	service.New("worker").Run(blockUntilDone).Register(c)
+
+	state, err := c.ServiceState("worker")
+	require.NoError(t, err)
+	assert.Equal(t, service.StateRegistered, state)
+	assert.False(t, c.IsServiceRunning("worker"))
+	_, err = c.ServiceState("unknown")
+	assert.Error(t, err)
+
+	require.NoError(t, c.StartAll(context.Background()))
+	state, err = c.ServiceState("worker")
+	require.NoError(t, err)
+	assert.Equal(t, service.StateRunning, state)
+	assert.True(t, c.IsServiceRunning("worker"))
+
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	state, err = c.ServiceState("worker")
+	require.NoError(t, err)
+	assert.Equal(t, service.StateStopped, state)
+	assert.False(t, c.IsServiceRunning("worker"))
+}
+
+func TestServiceState_stopping(t *testing.T) {
+	c := service.NewContainer()
+	release := make(chan struct{})
+	service.New("slow").Run(func(ctx context.Context) error {
+		<-ctx.Done()
+		<-release
+		return nil
+	}).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	c.StopAll()
+	assert.Eventually(t, func() bool {
+		state, _ := c.ServiceState("slow")
+		return state == service.StateStopping
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, c.IsServiceRunning("slow"))
+
+	close(release)
+	require.NoError(t, c.WaitAllStopped(context.Background()))
+	state, _ := c.ServiceState("slow")
+	assert.Equal(t, service.StateStopped, state)
+}
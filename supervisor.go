@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrRestartIntensity is wrapped by the error passed to Supervision.Escalate
+var ErrRestartIntensity = errors.New("supervisor restart intensity exceeded")
+
+// SupervisorStrategy defines which services are restarted when a service fails, see WithSupervisor
+type SupervisorStrategy int
+
+const (
+	// OneForOne restarts only the failed service
+	OneForOne SupervisorStrategy = iota
+	// OneForAll stops all other services and restarts all services in start order
+	OneForAll
+	// RestForOne stops the services started after the failed service and restarts them together
+	// with the failed service in start order
+	RestForOne
+)
+
+func (s SupervisorStrategy) String() string {
+	switch s {
+	case OneForOne:
+		return "OneForOne"
+	case OneForAll:
+		return "OneForAll"
+	case RestForOne:
+		return "RestForOne"
+	default:
+		return "Unknown"
+	}
+}
+
+// Supervision configures the supervisor of a container, see WithSupervisor
+type Supervision struct {
+	Strategy SupervisorStrategy
+	// Intensity is the max number of supervisor restarts within Period, default is 1.
+	// When exceeded all services are stopped like without supervisor, unless Escalate is set.
+	Intensity int
+	// Period for the Intensity, default is 5s
+	Period time.Duration
+	// Escalate is called instead of stopping all services when the Intensity is exceeded,
+	// e.g. to notify a parent container. The error wraps ErrRestartIntensity and the error of the failed service.
+	// The failed service is not restarted anymore, the other services keep running.
+	Escalate func(err error)
+}
+
+// WithSupervisor restarts failed services according to the strategy instead of stopping the container.
+// The supervisor takes over when Run returned with an error and the restart policy of the service,
+// see WithRestartPolicy, does not restart it anymore. Services stopped by name are not supervised.
+// More restarts than the Intensity within the Period are escalated, see Supervision.Escalate.
+func WithSupervisor(s Supervision) Option {
+	if s.Intensity <= 0 {
+		s.Intensity = 1
+	}
+	if s.Period <= 0 {
+		s.Period = 5 * time.Second
+	}
+	return func(c *Container) {
+		c.supervisor = &supervisor{Supervision: s}
+	}
+}
+
+type supervisor struct {
+	Supervision
+	// mu serializes restarts, so concurrent failures do not restart the same services twice
+	mu       sync.Mutex
+	restarts []time.Time
+}
+
+// allowRestart records a restart and returns false when the intensity is exceeded
+func (s *supervisor) allowRestart(now time.Time) bool {
+	s.restarts = slices.DeleteFunc(s.restarts, func(t time.Time) bool {
+		return now.Sub(t) > s.Period
+	})
+	if len(s.restarts) >= s.Intensity {
+		return false
+	}
+	s.restarts = append(s.restarts, now)
+	return true
+}
+
+// supervise restarts the services affected by the failure of rc according to the supervisor strategy.
+// Exceeding the restart intensity is escalated, when a restart fails all services are stopped.
+func (c *Container) supervise(rc *runContext, runErr error) {
+	sup := c.supervisor
+	sup.mu.Lock()
+	defer sup.mu.Unlock()
+
+	name := rc.service.name
+	c.mu.Lock()
+	replaced := c.runContexts[name] != rc
+	shuttingDown := c.shuttingDown
+	ctx := c.runCtx
+	var affected []*runContext
+	for _, other := range c.runContexts {
+		switch {
+		case other == rc:
+		case sup.Strategy == OneForAll:
+		case sup.Strategy == RestForOne && other.seq > rc.seq:
+		default:
+			continue
+		}
+		affected = append(affected, other)
+	}
+	c.mu.Unlock()
+	if replaced || shuttingDown {
+		// The service was already restarted by an earlier failure or the container is stopping
+		return
+	}
+
+	logger := c.serviceLogger(rc.service)
+	if !sup.allowRestart(time.Now()) {
+		c.escalate(rc.service, runErr)
+		return
+	}
+
+	slices.SortFunc(affected, func(a, b *runContext) int {
+		return a.seq - b.seq
+	})
+	logger.Warn("Supervisor restarting services", "strategy", sup.Strategy, "error", runErr, "services", len(affected))
+	for i := len(affected) - 1; i >= 0; i-- {
+		if err := c.Stop(ctx, affected[i].service.name); err != nil {
+			logger.Error("Supervisor failed to stop service", "service", affected[i].service.name, "error", err)
+			c.stopAll(fmt.Errorf("supervisor failed to stop service '%s': %w", affected[i].service.name, err))
+			return
+		}
+	}
+	for _, a := range affected {
+		c.emitEvent(EventRestarting, a.service.name, runErr)
+		if err := c.Restart(ctx, a.service.name); err != nil {
+			logger.Error("Supervisor failed to restart service", "service", a.service.name, "error", err)
+			c.stopAll(fmt.Errorf("supervisor failed to restart service '%s': %w", a.service.name, err))
+			return
+		}
+	}
+}
+
+// escalate handles a failure that exceeded the restart intensity of the supervisor.
+// The failure is recorded as crash loop, see CodeCrashLoop.
+func (c *Container) escalate(s *serviceInfo, runErr error) {
+	sup := c.supervisor
+	err := fmt.Errorf("%w, %d restarts within %s: service '%s' failed: %w", ErrRestartIntensity, sup.Intensity, sup.Period, s.name, runErr)
+	c.recordError(s, LifecycleRun, err)
+	if sup.Escalate != nil {
+		c.log.Error("Escalating service failure", "error", err, "container", c.name)
+		c.callSafe("supervisor escalation", func() {
+			sup.Escalate(err)
+		})
+		return
+	}
+	c.log.Error("Stopping all services", "error", err, "container", c.name)
+	c.stopAll(err)
+}
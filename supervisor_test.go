@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// supervisedService fails once on the first run and counts its runs
+type supervisedService struct {
+	runs     atomic.Int32
+	failOnce bool
+}
+
+func (s *supervisedService) run(ctx context.Context) error {
+	if s.runs.Add(1) == 1 && s.failOnce {
+		return errors.New("crashed")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func startSupervised(t *testing.T, strategy service.SupervisorStrategy) (*service.Container, []*supervisedService) {
+	c := service.NewContainer(service.WithSupervisor(service.Supervision{Strategy: strategy}))
+	services := []*supervisedService{{}, {failOnce: true}, {}}
+	for i, s := range services {
+		service.New([]string{"first", "failing", "last"}[i]).Run(s.run).Register(c)
+	}
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Eventually(t, func() bool {
+		return services[1].runs.Load() == 2
+	}, time.Second, 5*time.Millisecond)
+	return c, services
+}
+
+func TestWithSupervisor_oneForOne(t *testing.T) {
+	c, services := startSupervised(t, service.OneForOne)
+	assert.Equal(t, 3, c.RunningCount())
+	assert.Equal(t, int32(1), services[0].runs.Load())
+	assert.Equal(t, int32(1), services[2].runs.Load())
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestWithSupervisor_oneForAll(t *testing.T) {
+	c, services := startSupervised(t, service.OneForAll)
+	assert.Eventually(t, func() bool {
+		return c.RunningCount() == 3
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(2), services[0].runs.Load())
+	assert.Equal(t, int32(2), services[2].runs.Load())
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestWithSupervisor_restForOne(t *testing.T) {
+	c, services := startSupervised(t, service.RestForOne)
+	assert.Eventually(t, func() bool {
+		return c.RunningCount() == 3
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(1), services[0].runs.Load())
+	assert.Equal(t, int32(2), services[2].runs.Load())
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
+
+func TestWithSupervisor_intensityExceeded(t *testing.T) {
+	alerts := make(chan service.Alert, 10)
+	c := service.NewContainer(service.WithSupervisor(service.Supervision{Intensity: 2, Period: time.Minute}),
+		service.WithAlerter(func(a service.Alert) {
+			alerts <- a
+		}))
+	var runs atomic.Int32
+	service.New("failing").Run(func(ctx context.Context) error {
+		runs.Add(1)
+		return errors.New("crashed")
+	}).Register(c)
+	service.New("other").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	c.WaitAllStopped(ctx)
+	require.NoError(t, ctx.Err())
+	assert.Equal(t, int32(3), runs.Load())
+
+	errs := c.Errors()
+	require.NotEmpty(t, errs)
+	assert.Equal(t, service.CodeCrashLoop, errs[len(errs)-1].Code)
+	select {
+	case a := <-alerts:
+		assert.Equal(t, service.CodeCrashLoop, a.Code)
+		assert.Equal(t, service.SeverityCritical, a.Severity)
+	case <-time.After(time.Second):
+		t.Fatal("crash loop not alerted")
+	}
+}
+
+func TestWithSupervisor_escalate(t *testing.T) {
+	escalated := make(chan error, 1)
+	c := service.NewContainer(service.WithSupervisor(service.Supervision{
+		Intensity: 1,
+		Period:    time.Minute,
+		Escalate: func(err error) {
+			escalated <- err
+		},
+	}))
+	runErr := errors.New("crashed")
+	service.New("failing").Run(func(ctx context.Context) error {
+		return runErr
+	}).Register(c)
+	service.New("other").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	select {
+	case err := <-escalated:
+		assert.ErrorIs(t, err, service.ErrRestartIntensity)
+		assert.ErrorIs(t, err, runErr)
+	case <-time.After(time.Second):
+		t.Fatal("expected escalation")
+	}
+	assert.Equal(t, 1, c.RunningCount(), "other service must keep running")
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+}
@@ -0,0 +1,11 @@
+package service
+
+// WithLockedOSThread runs the service on a dedicated OS thread, e.g. for services wrapping C libraries or GUI loops
+// that require thread affinity. The goroutine calling Run is locked to its OS thread with runtime.LockOSThread,
+// restarts of Run are called on the same thread. The thread is unlocked when the service stopped.
+// Init is not affected, it is called on the goroutine calling StartAll. Goroutines started by Run are not locked.
+func WithLockedOSThread() RegisterOption {
+	return func(s *serviceInfo) {
+		s.lockOSThread = true
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TickerOption configures NewTicker
+type TickerOption func(t *tickerConfig)
+
+type tickerConfig struct {
+	immediate   bool
+	maxFailures int
+}
+
+// WithImmediateTick calls the function of the ticker once right after Run was called, before the first tick
+func WithImmediateTick() TickerOption {
+	return func(t *tickerConfig) {
+		t.immediate = true
+	}
+}
+
+// WithMaxFailures lets Run return an error when the function of the ticker failed n times in a row,
+// so the container handles the service as failed, e.g. stops all services. 0 means unlimited, default is 0.
+func WithMaxFailures(n int) TickerOption {
+	return func(t *tickerConfig) {
+		t.maxFailures = n
+	}
+}
+
+// NewTicker returns a service that calls fn on every tick of interval until the context of Run is canceled.
+// Ticks are dropped while fn is still running. Errors and panics of fn are logged, see PanicError and WithMaxFailures.
+// Init of the service fails when interval is not positive.
+func NewTicker(name string, interval time.Duration, fn func(ctx context.Context) error, opts ...TickerOption) Runner {
+	if interval <= 0 {
+		err := fmt.Errorf("ticker '%s' needs a positive interval, got %s", name, interval)
+		fail := func(ctx context.Context) error {
+			return err
+		}
+		return &genericService{name: name, init: fail, run: fail}
+	}
+	cfg := &tickerConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	return &genericService{
+		name: name,
+		run: func(ctx context.Context) error {
+			logger := LoggerFromContext(ctx)
+			failures := 0
+			tick := func() error {
+				err := callIteration(ctx, fn)
+				if err == nil || ctx.Err() != nil {
+					failures = 0
+					return nil
+				}
+				failures++
+				if cfg.maxFailures > 0 && failures >= cfg.maxFailures {
+					return fmt.Errorf("ticker failed %d times in a row: %w", failures, err)
+				}
+				logger.Warn("Tick failed", "error", err, "failures", failures)
+				return nil
+			}
+
+			if cfg.immediate {
+				if err := tick(); err != nil {
+					return err
+				}
+			}
+			t := time.NewTicker(interval)
+			defer t.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-t.C:
+				}
+				if err := tick(); err != nil {
+					return err
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTicker(t *testing.T) {
+	ticks := atomic.Int32{}
+	c := service.NewContainer()
+	c.Register(service.NewTicker("poller", 5*time.Millisecond, func(ctx context.Context) error {
+		if ticks.Add(1) == 1 {
+			return errors.New("temporary")
+		}
+		return nil
+	}))
+	require.NoError(t, c.StartAll(context.Background()))
+	assert.Equal(t, int32(0), ticks.Load(), "first tick after the interval")
+	require.Eventually(t, func() bool {
+		return ticks.Load() >= 3
+	}, time.Second, time.Millisecond)
+	assert.True(t, c.IsServiceRunning("poller"))
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
+
+func TestNewTicker_immediate(t *testing.T) {
+	ticked := make(chan struct{}, 1)
+	c := service.NewContainer()
+	c.Register(service.NewTicker("poller", time.Hour, func(ctx context.Context) error {
+		ticked <- struct{}{}
+		return nil
+	}, service.WithImmediateTick()))
+	require.NoError(t, c.StartAll(context.Background()))
+	select {
+	case <-ticked:
+	case <-time.After(time.Second):
+		t.Fatal("no immediate tick")
+	}
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+}
+
+func TestNewTicker_maxFailures(t *testing.T) {
+	tickErr := errors.New("broken")
+	ticks := atomic.Int32{}
+	c := service.NewContainer()
+	c.Register(service.NewTicker("poller", time.Millisecond, func(ctx context.Context) error {
+		ticks.Add(1)
+		panic(tickErr)
+	}, service.WithMaxFailures(3)))
+	service.New("other").Run(blockUntilDone).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+
+	err := c.WaitAllStopped(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ticker failed 3 times in a row")
+	var panicErr *service.PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, int32(3), ticks.Load())
+	assert.Equal(t, service.ContainerFailed, c.State())
+}
+
+func TestNewTicker_invalidInterval(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(service.NewTicker("poller", 0, func(ctx context.Context) error {
+		return nil
+	}))
+
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "needs a positive interval, got 0s")
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+)
+
+// Span names used for the lifecycle of the container, see Tracer
+const (
+	SpanStartAll = "service.StartAll"
+	SpanInit     = "service.Init"
+	SpanRun      = "service.Run"
+	SpanShutdown = "service.Shutdown"
+	SpanStop     = "service.Stop"
+)
+
+// Span attribute keys, see Tracer
+const (
+	AttrContainer = "go_service.container"
+	AttrService   = "go_service.service"
+	AttrRunID     = "go_service.run_id"
+)
+
+// Tracer creates spans around the lifecycle of the container and its services, see WithTracer.
+// The StartAll span is parented from the context passed to StartAll, Init and Run spans are children of it.
+// All values of the context passed to StartAll, e.g. baggage, are propagated to Init and Run.
+// Services start their own child spans with StartSpan.
+// The Shutdown span contains a Stop span per service.
+type Tracer interface {
+	// Start starts a span as child of the span in ctx and returns the context containing the new span.
+	// end is called exactly once with the error the span ended with.
+	Start(ctx context.Context, name string, attrs map[string]string) (spanCtx context.Context, end func(err error))
+}
+
+// WithTracer traces the lifecycle of the container, e.g. with OpenTelemetry.
+// By default no spans are created.
+func WithTracer(t Tracer) Option {
+	return func(c *Container) {
+		c.tracer = t
+	}
+}
+
+// startSpan starts a span with the given tracer, the service is optional
+func (c *Container) startSpan(ctx context.Context, name string, s *serviceInfo) (context.Context, func(err error)) {
+	if c.tracer == nil {
+		return ctx, func(err error) {}
+	}
+	attrs := map[string]string{
+		AttrContainer: c.name,
+		AttrRunID:     c.RunID(),
+	}
+	if s != nil {
+		attrs[AttrService] = s.name
+	}
+	return c.tracer.Start(ctx, name, attrs)
+}
+
+type spanStarterKey struct{}
+
+// spanStarter starts spans with the tracer of the container for a single service, see StartSpan
+type spanStarter func(ctx context.Context, name string) (context.Context, func(err error))
+
+// StartSpan starts a span as child of the span in ctx with the Tracer of the container, e.g. inside Init or Run
+// to trace the work of a service as part of the startup trace. The span has the attributes of the service.
+// end must be called exactly once. Without a tracer, ctx is returned and no span is created, see WithTracer.
+func StartSpan(ctx context.Context, name string) (spanCtx context.Context, end func(err error)) {
+	if start, ok := ctx.Value(spanStarterKey{}).(spanStarter); ok {
+		return start(ctx, name)
+	}
+	return ctx, func(err error) {}
+}
+
+// withSpanStarter adds the tracer of the container for the service to ctx, see StartSpan
+func (c *Container) withSpanStarter(ctx context.Context, s *serviceInfo) context.Context {
+	if c.tracer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanStarterKey{}, spanStarter(func(ctx context.Context, name string) (context.Context, func(err error)) {
+		return c.startSpan(ctx, name, s)
+	}))
+}
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type spanKey struct{}
+
+// recordingTracer records the names of ended spans with the name of their parent span
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []string
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, attrs map[string]string) (context.Context, func(err error)) {
+	if svc, ok := attrs[service.AttrService]; ok {
+		name += " " + svc
+	}
+	parent, _ := ctx.Value(spanKey{}).(string)
+	return context.WithValue(ctx, spanKey{}, name), func(err error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.spans = append(r.spans, parent+" > "+name)
+	}
+}
+
+func TestWithTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	c := service.NewContainer(service.WithTracer(tracer))
+	service.New("worker").Init(func(ctx context.Context) error {
+		return nil
+	}).Run(blockUntilDone).Register(c)
+
+	ctx := context.WithValue(context.Background(), spanKey{}, "request")
+	require.NoError(t, c.StartAll(ctx))
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+
+	require.Eventually(t, func() bool {
+		tracer.mu.Lock()
+		defer tracer.mu.Unlock()
+		return len(tracer.spans) == 5
+	}, time.Second, 5*time.Millisecond)
+	assert.ElementsMatch(t, []string{
+		"service.StartAll > service.Init worker",
+		"request > service.StartAll",
+		"service.StartAll > service.Run worker",
+		"service.Shutdown > service.Stop worker",
+		"service.StartAll > service.Shutdown",
+	}, tracer.spans)
+}
+
+type baggageKey struct{}
+
+func TestStartSpan(t *testing.T) {
+	tracer := &recordingTracer{}
+	c := service.NewContainer(service.WithTracer(tracer))
+	var baggage any
+	service.New("db").Init(func(ctx context.Context) error {
+		baggage = ctx.Value(baggageKey{})
+		_, end := service.StartSpan(ctx, "migrate")
+		end(nil)
+		return nil
+	}).Run(blockUntilDone).Register(c)
+
+	ctx := context.WithValue(context.Background(), baggageKey{}, "tenant=a")
+	require.NoError(t, c.StartAll(ctx))
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+
+	assert.Equal(t, "tenant=a", baggage)
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	assert.Contains(t, tracer.spans, "service.Init db > migrate db")
+}
+
+func TestStartSpan_withoutTracer(t *testing.T) {
+	ctx := context.Background()
+	spanCtx, end := service.StartSpan(ctx, "work")
+	end(nil)
+	assert.Equal(t, ctx, spanCtx)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnhealthy is wrapped by the error of a Run that was canceled because of failing health checks,
+// see WithRestartOnUnhealthy
+var ErrUnhealthy = errors.New("service unhealthy")
+
+// WithHealthCheckInterval sets the interval of the health checks of services restarted when unhealthy,
+// see WithRestartOnUnhealthy. Each check is canceled after the interval. Default is 10s.
+func WithHealthCheckInterval(d time.Duration) Option {
+	return func(c *Container) {
+		c.healthCheckInterval = d
+	}
+}
+
+// WithRestartOnUnhealthy restarts Run of a service implementing HealthChecker when the given number of
+// consecutive health checks failed, even though Run did not return. The context of Run is canceled with
+// a cause wrapping ErrUnhealthy and Run is called again after the backoff, see WithRestartPolicy.
+// When Backoff.MaxRestarts is exceeded, the service is handled as failed.
+func WithRestartOnUnhealthy(threshold int, backoff Backoff) RegisterOption {
+	return func(s *serviceInfo) {
+		s.unhealthyThreshold = threshold
+		s.unhealthyBackoff = backoff.withDefaults()
+	}
+}
+
+// runWatched calls run and cancels it when the service does not become ready within the startup timeout
+// or the health checks of the service fail too often, see WithStartupTimeout and WithRestartOnUnhealthy.
+// Returns an error wrapping ErrStartupTimeout or ErrUnhealthy in that case.
+func (c *Container) runWatched(ctx context.Context, rc *runContext, run func(ctx context.Context) error) error {
+	s := rc.service
+	watchedCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go c.probeStartup(watchedCtx, rc, cancel)
+	if checker, ok := s.service.(HealthChecker); ok && s.unhealthyThreshold > 0 {
+		go c.watchHealth(watchedCtx, s, checker, cancel)
+	}
+
+	err := run(watchedCtx)
+	cause := context.Cause(watchedCtx)
+	if ctx.Err() == nil && (errors.Is(cause, ErrUnhealthy) || errors.Is(cause, ErrStartupTimeout)) {
+		return cause
+	}
+	return err
+}
+
+// watchHealth checks the health of the service periodically and cancels ctx once the threshold is reached
+func (c *Container) watchHealth(ctx context.Context, s *serviceInfo, checker HealthChecker, cancel context.CancelCauseFunc) {
+	logger := c.serviceLogger(s)
+	t := time.NewTicker(c.healthCheckInterval)
+	defer t.Stop()
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		checkCtx, cancelCheck := context.WithTimeout(ctx, c.healthCheckInterval)
+		err := checkHealth(checkCtx, checker)
+		cancelCheck()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			failures = 0
+			continue
+		}
+		failures++
+		logger.Warn("Health check failed", "error", err, "failures", failures, "threshold", s.unhealthyThreshold)
+		if failures == 1 {
+			c.alert(s.name, CodeUnhealthy, SeverityWarning, "service health degraded", err)
+		}
+		if failures >= s.unhealthyThreshold {
+			cancel(fmt.Errorf("%w after %d failed health checks: %w", ErrUnhealthy, failures, err))
+			return
+		}
+	}
+}
+
+// checkHealth calls the health check, panics are returned as *PanicError
+func checkHealth(ctx context.Context, checker HealthChecker) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("health check panicked: %w", newPanicError(r))
+		}
+	}()
+	return checker.Health(ctx)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// WithStrictRegistration verifies every service on Register for conflicting or suspicious declarations,
+// e.g. a service that is initialized before and after the same init barrier.
+// Problems are logged as warnings and added to the warnings of the StartReport.
+func WithStrictRegistration() Option {
+	return func(c *Container) {
+		c.strictRegistration = true
+	}
+}
+
+// verifyService returns a warning for every problem found in the declaration of the service
+func verifyService(info *serviceInfo) []string {
+	var warnings []string
+	if info.name == "" {
+		warnings = append(warnings, "service has an empty name")
+	}
+	if v := reflect.ValueOf(info.service); !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		warnings = append(warnings, fmt.Sprintf("service '%s' is nil", info.name))
+	}
+	_, isReadier := info.service.(Readier)
+	_, isReadyWaiter := info.service.(ReadyWaiter)
+	if isReadier && isReadyWaiter {
+		warnings = append(warnings, fmt.Sprintf("service '%s' implements Readier and the deprecated ReadyWaiter, only Ready is used", info.name))
+	}
+	for _, b := range info.initBefore {
+		if slices.Contains(info.initAfter, b) {
+			warnings = append(warnings, fmt.Sprintf("service '%s' is initialized before and after barrier '%s'", info.name, b))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,47 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictRegistration(t *testing.T) {
+	buf := &bytes.Buffer{}
+	c := service.NewContainer(service.WithStrictRegistration(), service.WithInitBarrier("db"))
+	c.SetLogger(slog.New(slog.NewTextHandler(buf, nil)))
+
+	service.New("ok").InitAfter("db").Register(c)
+	assert.NotContains(t, buf.String(), "Suspicious service registration")
+
+	var nilService *readyService
+	c.Register(nilService)
+	assert.Contains(t, buf.String(), "is nil")
+}
+
+func TestStrictRegistration_emptyName(t *testing.T) {
+	c := service.NewContainer(service.WithStrictRegistration())
+	service.New("").Register(c)
+
+	err := c.StartAll(context.Background())
+	require.NoError(t, err)
+	c.StopAll()
+	c.WaitAllStopped(context.Background())
+	report := c.StartReport()
+	assert.Equal(t, []string{"service has an empty name"}, report.Warnings)
+}
+
+func TestStrictRegistration_barrierConflict(t *testing.T) {
+	c := service.NewContainer(service.WithStrictRegistration(), service.WithInitBarrier("db"))
+	service.New("conflict").InitBefore("db").InitAfter("db").Register(c)
+
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	report := c.StartReport()
+	assert.Equal(t, []string{"service 'conflict' is initialized before and after barrier 'db'"}, report.Warnings)
+}
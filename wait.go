@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// WaitAllReadyTimeout is like WaitAllReady with a timeout instead of a context
+func (c *Container) WaitAllReadyTimeout(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return c.WaitAllReady(ctx)
+}
+
+// WaitAllStoppedTimeout is like WaitAllStopped with a timeout instead of a context
+func (c *Container) WaitAllStoppedTimeout(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return c.WaitAllStopped(ctx)
+}
+
+// WaitServiceStoppedTimeout is like WaitServiceStopped with a timeout instead of a context
+func (c *Container) WaitServiceStoppedTimeout(name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return c.WaitServiceStopped(ctx, name)
+}
@@ -0,0 +1,31 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitTimeouts(t *testing.T) {
+	c := service.NewContainer()
+	service.New("api").Run(blockUntilDone).Register(c)
+	service.New("job").Run(func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}).Critical(false).Register(c)
+	require.NoError(t, c.StartAll(context.Background()))
+	require.NoError(t, c.WaitAllReadyTimeout(time.Second))
+
+	assert.Error(t, c.WaitServiceStoppedTimeout("api", 10*time.Millisecond))
+	assert.NoError(t, c.WaitServiceStoppedTimeout("job", time.Second))
+	assert.True(t, c.IsServiceRunning("api"))
+	assert.Error(t, c.WaitServiceStoppedTimeout("unknown", time.Second))
+
+	assert.ErrorIs(t, c.WaitAllStoppedTimeout(10*time.Millisecond), context.DeadlineExceeded)
+	c.StopAll()
+	assert.NoError(t, c.WaitAllStoppedTimeout(time.Second))
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// NewWorkerPool returns a service that runs fn in the given number of goroutines with a shared context.
+// When a worker returns an error or panics, the context of all workers is canceled. Run returns when
+// all workers returned, with the joined errors of the workers.
+// Init of the service fails when workers is less than 1.
+func NewWorkerPool(name string, workers int, fn RunFunc) Runner {
+	if workers < 1 {
+		err := fmt.Errorf("worker pool '%s' needs at least 1 worker, got %d", name, workers)
+		fail := func(ctx context.Context) error {
+			return err
+		}
+		return &genericService{name: name, init: fail, run: fail}
+	}
+	return &genericService{
+		name: name,
+		run: func(ctx context.Context) error {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			errs := make([]error, workers)
+			wg := sync.WaitGroup{}
+			wg.Add(workers)
+			for i := range workers {
+				go func() {
+					defer wg.Done()
+					if err := callIteration(ctx, fn); err != nil {
+						errs[i] = fmt.Errorf("worker %d: %w", i, err)
+						cancel()
+					}
+				}()
+			}
+			wg.Wait()
+			return errors.Join(errs...)
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niondir/go-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWorkerPool(t *testing.T) {
+	running := atomic.Int32{}
+	stopped := atomic.Int32{}
+	c := service.NewContainer()
+	c.Register(service.NewWorkerPool("consumers", 4, func(ctx context.Context) error {
+		running.Add(1)
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		stopped.Add(1)
+		return nil
+	}))
+	require.NoError(t, c.StartAll(context.Background()))
+	require.Eventually(t, func() bool {
+		return running.Load() == 4
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, c.StopAllAndWait(context.Background()))
+	assert.Equal(t, int32(4), stopped.Load())
+}
+
+func TestNewWorkerPool_error(t *testing.T) {
+	workerErr := errors.New("queue closed")
+	started := atomic.Int32{}
+	c := service.NewContainer()
+	c.Register(service.NewWorkerPool("consumers", 3, func(ctx context.Context) error {
+		if started.Add(1) == 2 {
+			return workerErr
+		}
+		<-ctx.Done()
+		return nil
+	}))
+	require.NoError(t, c.StartAll(context.Background()))
+
+	err := c.WaitAllStopped(context.Background())
+	assert.ErrorIs(t, err, workerErr)
+	assert.Contains(t, err.Error(), "worker ")
+	assert.Equal(t, service.ContainerFailed, c.State())
+}
+
+func TestNewWorkerPool_invalidWorkers(t *testing.T) {
+	c := service.NewContainer()
+	c.Register(service.NewWorkerPool("consumers", -1, blockUntilDone))
+
+	err := c.StartAll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "needs at least 1 worker, got -1")
+}